@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+// jobAction is a bulk status change exposed as a subcommand.
+type jobAction struct {
+	use     string
+	short   string
+	verb    string // past tense, printed per changed job
+	skipped string // why an ID might not have been changed
+	run     func(context.Context, *db.Client, []int64) ([]int64, error)
+}
+
+func init() {
+	for _, action := range []jobAction{
+		{
+			use:     "retry",
+			short:   "Move failed, cancelled or aborted jobs back to todo",
+			verb:    "retried",
+			skipped: "not found or not failed, cancelled or aborted",
+			run:     db.RetryJobs,
+		},
+		{
+			use:     "cancel",
+			short:   "Cancel todo jobs",
+			verb:    "cancelled",
+			skipped: "not found or not todo",
+			run:     db.CancelJobs,
+		},
+		{
+			use:     "abort",
+			short:   "Request abortion of doing jobs",
+			verb:    "abort requested for",
+			skipped: "not found or not doing",
+			run:     db.AbortJobs,
+		},
+	} {
+		rootCmd.AddCommand(newJobActionCmd(action))
+	}
+}
+
+func newJobActionCmd(action jobAction) *cobra.Command {
+	return &cobra.Command{
+		Use:   action.use + " <job-id>...",
+		Short: action.short,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := parseJobIDs(args)
+			if err != nil {
+				return err
+			}
+
+			_, conn, client, err := openClient()
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			if conn.IsReadOnly() {
+				return fmt.Errorf("%s: connection %q is read-only", action.use, conn.Name)
+			}
+
+			changed, err := action.run(context.Background(), client, ids)
+			if err != nil {
+				return fmt.Errorf("%s: %w", action.use, err)
+			}
+
+			out := cmd.OutOrStdout()
+			for _, id := range changed {
+				fmt.Fprintf(out, "%s job #%d\n", action.verb, id)
+			}
+
+			if skipped := missingIDs(ids, changed); len(skipped) > 0 {
+				return fmt.Errorf("skipped (%s): %s", action.skipped, formatJobIDs(skipped))
+			}
+			return nil
+		},
+	}
+}
+
+// parseJobIDs parses job ID arguments, accepting an optional leading '#'.
+func parseJobIDs(args []string) ([]int64, error) {
+	ids := make([]int64, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid job id %q", arg)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// missingIDs returns the IDs in want that are not present in got.
+func missingIDs(want, got []int64) []int64 {
+	seen := make(map[int64]bool, len(got))
+	for _, id := range got {
+		seen[id] = true
+	}
+	var missing []int64
+	for _, id := range want {
+		if !seen[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// formatJobIDs renders IDs as a comma-separated "#1, #2" list.
+func formatJobIDs(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("#%d", id)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/matthewmyrick/procrastinate-cli/config"
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+// loadConfig reads the config file and resolves which connection to use:
+// the --connection flag override, or the first one in the list.
+func loadConfig() (*config.Config, *config.Connection, error) {
+	cfgPath, err := config.FindConfigPath(configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("config: %w", err)
+	}
+
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("config: %w", err)
+	}
+
+	connName := cfg.Connections[0].Name
+	if connection != "" {
+		connName = connection
+	}
+	conn, err := cfg.GetConnection(connName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connection %q: %w", connName, err)
+	}
+
+	return cfg, conn, nil
+}
+
+// openClient loads the config and connects to the selected database.
+// Used by the non-interactive subcommands; the caller must Close the client.
+func openClient() (*config.Config, *config.Connection, *db.Client, error) {
+	cfg, conn, err := loadConfig()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	client, err := db.NewClient(context.Background(), config.ConnString(conn))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("connect to %s: %w", conn.Name, err)
+	}
+
+	return cfg, conn, client, nil
+}
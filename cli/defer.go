@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+var (
+	deferArgs         string
+	deferPriority     int
+	deferLock         string
+	deferQueueingLock string
+	deferAt           string
+	deferIn           string
+)
+
+var deferCmd = &cobra.Command{
+	Use:   "defer <task>",
+	Short: "Enqueue a job through Procrastinate's defer function",
+	Long: `Enqueue a job through Procrastinate's own defer SQL function, so it is
+picked up exactly like a job deferred by the application.
+
+The job goes to --queue, or the connection's default queue. --at takes a
+time (2006-01-02T15:04, RFC 3339) and --in a delay (30s, 10m, 2d).`,
+	Example: `  procrastinate-cli defer send_email --args '{"to": "a@example.com"}'
+  procrastinate-cli defer sync_account -q sync --queueing-lock account-42 --in 10m`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDefer,
+}
+
+func init() {
+	deferCmd.Flags().StringVarP(&deferArgs, "args", "a", "{}", "job arguments as a JSON object")
+	deferCmd.Flags().IntVarP(&deferPriority, "priority", "p", 0, "job priority; higher runs first")
+	deferCmd.Flags().StringVar(&deferLock, "lock", "", "lock: jobs sharing it run one at a time")
+	deferCmd.Flags().StringVar(&deferQueueingLock, "queueing-lock", "", "queueing lock: at most one todo job may hold it")
+	deferCmd.Flags().StringVar(&deferAt, "at", "", "run at this time instead of now")
+	deferCmd.Flags().StringVar(&deferIn, "in", "", "run after this delay instead of now")
+	deferCmd.MarkFlagsMutuallyExclusive("at", "in")
+	rootCmd.AddCommand(deferCmd)
+}
+
+func runDefer(cmd *cobra.Command, args []string) error {
+	req := db.DeferRequest{
+		TaskName: args[0],
+		Args:     json.RawMessage(deferArgs),
+		Priority: deferPriority,
+	}
+	if cmd.Flags().Changed("lock") {
+		req.Lock = &deferLock
+	}
+	if cmd.Flags().Changed("queueing-lock") {
+		req.QueueingLock = &deferQueueingLock
+	}
+
+	var err error
+	switch {
+	case deferAt != "":
+		req.ScheduledAt, err = db.ParseSchedule(deferAt, time.Now())
+	case deferIn != "":
+		req.ScheduledAt, err = db.ParseSchedule("+"+deferIn, time.Now())
+	}
+	if err != nil {
+		return err
+	}
+
+	_, conn, client, err := openClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if conn.IsReadOnly() {
+		return fmt.Errorf("defer: connection %q is read-only", conn.Name)
+	}
+
+	req.Queue = queueOrDefault(conn.DefaultQueue)
+	id, err := db.DeferJob(context.Background(), client, req)
+	var lockErr *db.QueueingLockError
+	if errors.As(err, &lockErr) {
+		return lockErr
+	}
+	if err != nil {
+		return fmt.Errorf("defer: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "deferred job #%d to queue %q\n", id, req.Queue)
+	return nil
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/cobra"
+
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+var (
+	listStatus string
+	listTask   string
+	listWhere  string
+	listLimit  int
+	listOutput string
+
+	showOutput string
+)
+
+var jobsCmd = &cobra.Command{
+	Use:     "jobs",
+	Aliases: []string{"job"},
+	Short:   "Inspect jobs without starting the TUI",
+}
+
+var jobsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List jobs in a queue as a table, JSON, CSV or NDJSON",
+	Args:  cobra.NoArgs,
+	RunE:  runJobsList,
+}
+
+var jobShowCmd = &cobra.Command{
+	Use:   "show <job-id>",
+	Short: "Show a job's fields, args and event timeline",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runJobShow,
+}
+
+func init() {
+	jobsListCmd.Flags().StringVarP(&listStatus, "status", "s", "", "only jobs with this status")
+	jobsListCmd.Flags().StringVarP(&listTask, "task", "t", "", "only tasks matching this glob (e.g. 'send_*')")
+	jobsListCmd.Flags().StringVarP(&listWhere, "where", "w", "", "filter expression, e.g. 'attempts>2 args.order_id=1003 scheduled<-1h'")
+	jobsListCmd.Flags().IntVarP(&listLimit, "limit", "l", 100, "maximum number of jobs to return")
+	jobsListCmd.Flags().StringVarP(&listOutput, "output", "o", outputTable, "output format: table, json, csv, ndjson")
+
+	jobShowCmd.Flags().StringVarP(&showOutput, "output", "o", outputText, "output format: text, json")
+
+	jobsCmd.AddCommand(jobsListCmd, jobShowCmd)
+	rootCmd.AddCommand(jobsCmd)
+}
+
+func runJobsList(cmd *cobra.Command, args []string) error {
+	if err := checkOutputFormat(listOutput, outputTable, outputJSON, outputCSV, outputNDJSON); err != nil {
+		return err
+	}
+	if listStatus != "" && !isKnownStatus(listStatus) {
+		return fmt.Errorf("invalid --status %q", listStatus)
+	}
+	if listLimit < 1 {
+		return fmt.Errorf("--limit must be at least 1")
+	}
+	where, err := db.ParseQuery(listWhere)
+	if err != nil {
+		return fmt.Errorf("invalid --where: %w", err)
+	}
+
+	_, conn, client, err := openClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	filter := db.JobFilter{
+		Queue:  queueOrDefault(conn.DefaultQueue),
+		Status: listStatus,
+		Task:   listTask,
+		Where:  where,
+	}
+	jobs, err := db.ListJobsFiltered(context.Background(), client, filter, listLimit, 0)
+	if err != nil {
+		return fmt.Errorf("listing jobs: %w", err)
+	}
+
+	return writeJobs(cmd.OutOrStdout(), jobs, listOutput)
+}
+
+func runJobShow(cmd *cobra.Command, args []string) error {
+	if err := checkOutputFormat(showOutput, outputText, outputJSON); err != nil {
+		return err
+	}
+	ids, err := parseJobIDs(args)
+	if err != nil {
+		return err
+	}
+
+	_, _, client, err := openClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	job, err := db.GetJob(ctx, client, ids[0])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("job #%d not found", ids[0])
+	}
+	if err != nil {
+		return fmt.Errorf("loading job: %w", err)
+	}
+	events, err := db.GetJobEvents(ctx, client, job.ID)
+	if err != nil {
+		return fmt.Errorf("loading events: %w", err)
+	}
+
+	return writeJobDetail(cmd.OutOrStdout(), *job, events, showOutput)
+}
+
+// queueOrDefault returns the --queue flag value, falling back to the
+// connection's default queue.
+func queueOrDefault(defaultQueue string) string {
+	if queue != "" {
+		return queue
+	}
+	return defaultQueue
+}
+
+func isKnownStatus(s string) bool {
+	for _, status := range db.AllStatuses() {
+		if string(status) == s {
+			return true
+		}
+	}
+	return false
+}
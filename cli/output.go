@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+// Output formats accepted by --output.
+const (
+	outputTable  = "table"
+	outputJSON   = "json"
+	outputCSV    = "csv"
+	outputNDJSON = "ndjson"
+	outputText   = "text"
+)
+
+// jobRecord is the machine-readable shape of a job for JSON/CSV output.
+type jobRecord struct {
+	ID             int64           `json:"id"`
+	Queue          string          `json:"queue"`
+	Task           string          `json:"task"`
+	Status         db.JobStatus    `json:"status"`
+	Priority       int             `json:"priority"`
+	Attempts       int             `json:"attempts"`
+	Lock           *string         `json:"lock"`
+	QueueingLock   *string         `json:"queueing_lock"`
+	ScheduledAt    *time.Time      `json:"scheduled_at"`
+	AbortRequested bool            `json:"abort_requested"`
+	WorkerID       *int64          `json:"worker_id"`
+	Args           json.RawMessage `json:"args"`
+}
+
+func newJobRecord(j db.Job) jobRecord {
+	args := j.Args
+	if len(args) == 0 {
+		args = json.RawMessage("null")
+	}
+	return jobRecord{
+		ID:             j.ID,
+		Queue:          j.QueueName,
+		Task:           j.TaskName,
+		Status:         j.Status,
+		Priority:       j.Priority,
+		Attempts:       j.Attempts,
+		Lock:           j.Lock,
+		QueueingLock:   j.QueueingLock,
+		ScheduledAt:    j.ScheduledAt,
+		AbortRequested: j.AbortRequested,
+		WorkerID:       j.WorkerID,
+		Args:           args,
+	}
+}
+
+// eventRecord is the machine-readable shape of a job event. SincePrevious is
+// the time elapsed since the job's previous event, in seconds.
+type eventRecord struct {
+	Type          string    `json:"type"`
+	At            time.Time `json:"at"`
+	SincePrevious *float64  `json:"since_previous_seconds"`
+}
+
+// jobDetailRecord is a job together with its event timeline.
+type jobDetailRecord struct {
+	jobRecord
+	Events []eventRecord `json:"events"`
+}
+
+func newJobDetailRecord(j db.Job, events []db.JobEvent) jobDetailRecord {
+	rec := jobDetailRecord{jobRecord: newJobRecord(j), Events: make([]eventRecord, len(events))}
+	for i, e := range events {
+		rec.Events[i] = eventRecord{Type: e.Type, At: e.At}
+		if i > 0 {
+			secs := e.At.Sub(events[i-1].At).Seconds()
+			rec.Events[i].SincePrevious = &secs
+		}
+	}
+	return rec
+}
+
+// writeJobDetail renders a single job and its events, either as JSON or as
+// the same labelled fields the TUI detail pane shows.
+func writeJobDetail(w io.Writer, j db.Job, events []db.JobEvent, format string) error {
+	if format == outputJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(newJobDetailRecord(j, events))
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	field := func(label, value string) {
+		fmt.Fprintf(tw, "%s:\t%s\n", label, value)
+	}
+
+	fmt.Fprintf(tw, "Job #%d\n\n", j.ID)
+	field("Task", j.TaskName)
+	field("Queue", j.QueueName)
+	field("Status", string(j.Status))
+	field("Priority", strconv.Itoa(j.Priority))
+	field("Attempts", strconv.Itoa(j.Attempts))
+	if j.Lock != nil {
+		field("Lock", *j.Lock)
+	}
+	if j.QueueingLock != nil {
+		field("Queueing Lock", *j.QueueingLock)
+	}
+	if j.ScheduledAt != nil {
+		field("Scheduled At", j.ScheduledAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	field("Abort Requested", strconv.FormatBool(j.AbortRequested))
+	if j.WorkerID != nil {
+		field("Worker ID", formatOptionalID(j.WorkerID))
+	} else {
+		field("Worker ID", "-")
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "\nArgs:")
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, j.Args, "  ", "  "); err == nil {
+		fmt.Fprintf(w, "  %s\n", pretty.String())
+	} else {
+		fmt.Fprintf(w, "  %s\n", j.Args)
+	}
+
+	if len(events) > 0 {
+		fmt.Fprintln(w, "\nEvents:")
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for i, e := range events {
+			gap := ""
+			if i > 0 {
+				gap = "+" + e.At.Sub(events[i-1].At).Round(time.Millisecond).String()
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", e.Type, e.At.Local().Format("2006-01-02 15:04:05.000"), gap)
+		}
+		return tw.Flush()
+	}
+	return nil
+}
+
+// checkOutputFormat validates an --output value against the allowed formats.
+func checkOutputFormat(format string, allowed ...string) error {
+	for _, f := range allowed {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid --output %q (want one of: %s)", format, strings.Join(allowed, ", "))
+}
+
+// writeJobs renders jobs in the requested output format.
+func writeJobs(w io.Writer, jobs []db.Job, format string) error {
+	switch format {
+	case outputJSON:
+		records := make([]jobRecord, len(jobs))
+		for i, j := range jobs {
+			records[i] = newJobRecord(j)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+
+	case outputNDJSON:
+		enc := json.NewEncoder(w)
+		for _, j := range jobs {
+			if err := enc.Encode(newJobRecord(j)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case outputCSV:
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{
+			"id", "queue", "task", "status", "priority", "attempts",
+			"lock", "queueing_lock", "scheduled_at", "abort_requested", "worker_id", "args",
+		})
+		for _, j := range jobs {
+			_ = cw.Write([]string{
+				strconv.FormatInt(j.ID, 10), j.QueueName, j.TaskName, string(j.Status),
+				strconv.Itoa(j.Priority), strconv.Itoa(j.Attempts),
+				derefString(j.Lock), derefString(j.QueueingLock), formatTimeRFC3339(j.ScheduledAt),
+				strconv.FormatBool(j.AbortRequested), formatOptionalID(j.WorkerID), string(j.Args),
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tQUEUE\tTASK\tSTATUS\tATTEMPTS\tSCHEDULED\tWORKER")
+		for _, j := range jobs {
+			scheduled := "-"
+			if j.ScheduledAt != nil {
+				scheduled = j.ScheduledAt.Local().Format("2006-01-02 15:04:05")
+			}
+			worker := "-"
+			if j.WorkerID != nil {
+				worker = formatOptionalID(j.WorkerID)
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%s\t%s\n",
+				j.ID, j.QueueName, j.TaskName, j.Status, j.Attempts, scheduled, worker)
+		}
+		return tw.Flush()
+	}
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func formatOptionalID(id *int64) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatInt(*id, 10)
+}
+
+func formatTimeRFC3339(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+var (
+	purgeOlderThan     string
+	purgeIncludeFailed bool
+	purgeAllQueues     bool
+	purgeBatch         int
+	purgeYes           bool
+)
+
+var purgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Delete old succeeded, cancelled and aborted jobs",
+	Long: `Delete finished jobs whose latest event is older than --older-than, like
+Procrastinate's remove_old_jobs: succeeded, cancelled and aborted jobs, plus
+failed ones with --include-failed. Their events are deleted with them.
+
+Without --yes this is a dry run that only prints how many jobs would be
+deleted in each queue and status. With --yes the counts are printed first,
+then jobs are deleted --batch-size at a time so no statement holds row locks
+for long.`,
+	Example: `  procrastinate-cli purge --older-than 30d
+  procrastinate-cli purge --older-than 30d --include-failed --all-queues --yes`,
+	Args: cobra.NoArgs,
+	RunE: runPurge,
+}
+
+func init() {
+	purgeCmd.Flags().StringVar(&purgeOlderThan, "older-than", "", "only jobs whose latest event is older than this (e.g. 72h, 30d)")
+	purgeCmd.Flags().BoolVar(&purgeIncludeFailed, "include-failed", false, "also delete failed jobs")
+	purgeCmd.Flags().BoolVar(&purgeAllQueues, "all-queues", false, "purge every queue instead of --queue")
+	purgeCmd.Flags().IntVar(&purgeBatch, "batch-size", db.DefaultPurgeBatch, "jobs deleted per statement")
+	purgeCmd.Flags().BoolVarP(&purgeYes, "yes", "y", false, "delete the jobs instead of only counting them")
+	purgeCmd.MarkFlagRequired("older-than")
+	rootCmd.AddCommand(purgeCmd)
+}
+
+func runPurge(cmd *cobra.Command, args []string) error {
+	age, err := db.ParseAge(purgeOlderThan)
+	if err != nil {
+		return fmt.Errorf("--older-than: %w", err)
+	}
+	if purgeAllQueues && queue != "" {
+		return fmt.Errorf("--all-queues and --queue are mutually exclusive")
+	}
+	if purgeBatch < 1 {
+		return fmt.Errorf("--batch-size must be at least 1")
+	}
+
+	_, conn, client, err := openClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if purgeYes && conn.IsReadOnly() {
+		return fmt.Errorf("purge: connection %q is read-only", conn.Name)
+	}
+
+	filter := db.PurgeFilter{
+		Queue:         queueOrDefault(conn.DefaultQueue),
+		OlderThan:     age,
+		IncludeFailed: purgeIncludeFailed,
+	}
+	if purgeAllQueues {
+		filter.Queue = db.AllQueues
+	}
+
+	ctx := context.Background()
+	counts, cutoff, err := db.PurgePreview(ctx, client, filter)
+	if err != nil {
+		return fmt.Errorf("purge: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	total, err := writePurgeCounts(out, counts)
+	if err != nil {
+		return err
+	}
+	if total == 0 {
+		fmt.Fprintln(out, "nothing to purge")
+		return nil
+	}
+	if !purgeYes {
+		fmt.Fprintf(out, "dry run: re-run with --yes to delete these %d job(s)\n", total)
+		return nil
+	}
+
+	deleted, err := db.PurgeJobs(ctx, client, filter, cutoff, purgeBatch)
+	if err != nil {
+		return fmt.Errorf("purge: deleted %d job(s) before failing: %w", deleted, err)
+	}
+	fmt.Fprintf(out, "deleted %d job(s)\n", deleted)
+	return nil
+}
+
+// writePurgeCounts renders the dry-run counts as a table and returns their
+// total.
+func writePurgeCounts(w io.Writer, counts []db.PurgeCount) (int64, error) {
+	var total int64
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "QUEUE\tSTATUS\tJOBS")
+	for _, c := range counts {
+		fmt.Fprintf(tw, "%s\t%s\t%d\n", c.Queue, c.Status, c.Jobs)
+		total += c.Jobs
+	}
+	fmt.Fprintf(tw, "(total)\t\t%d\n", total)
+	return total, tw.Flush()
+}
@@ -7,7 +7,6 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
-	"github.com/matthewmyrick/procrastinate-cli/config"
 	"github.com/matthewmyrick/procrastinate-cli/tui"
 )
 
@@ -15,18 +14,24 @@ var (
 	configPath string
 	queue      string
 	connection string
+	fleet      bool
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "procrastinate-cli",
 	Short: "TUI monitor for Procrastinate PostgreSQL task queue",
 	RunE:  runTUI,
+
+	// Execute prints the error itself; usage is only useful for flag mistakes.
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
 func init() {
-	rootCmd.Flags().StringVarP(&configPath, "config", "c", "", "path to config file")
-	rootCmd.Flags().StringVarP(&queue, "queue", "q", "", "queue to monitor (overrides connection default)")
-	rootCmd.Flags().StringVarP(&connection, "connection", "n", "", "connection name to use (defaults to first in config)")
+	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "path to config file")
+	rootCmd.PersistentFlags().StringVarP(&queue, "queue", "q", "", "queue to monitor (overrides connection default)")
+	rootCmd.PersistentFlags().StringVarP(&connection, "connection", "n", "", "connection name to use (defaults to first in config)")
+	rootCmd.Flags().BoolVar(&fleet, "fleet", false, "start on the fleet dashboard summarizing every connection")
 }
 
 // Execute runs the root command.
@@ -38,30 +43,20 @@ func Execute() {
 }
 
 func runTUI(cmd *cobra.Command, args []string) error {
-	cfgPath, err := config.FindConfigPath(configPath)
-	if err != nil {
-		return fmt.Errorf("config: %w", err)
-	}
-
-	cfg, err := config.Load(cfgPath)
+	cfg, conn, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("config: %w", err)
-	}
-
-	// Resolve which connection to start with: flag override, or first in list
-	connName := cfg.Connections[0].Name
-	if connection != "" {
-		connName = connection
-		if _, err := cfg.GetConnection(connName); err != nil {
-			return fmt.Errorf("connection %q: %w", connName, err)
-		}
+		return err
 	}
 
 	// Queue override from flag (empty means use connection's default_queue)
 	queueOverride := queue
 
 	// TUI boots immediately — DB connection happens inside the TUI
-	app := tui.NewApp(cfg, connName, queueOverride)
+	app := tui.NewApp(cfg, conn.Name, queueOverride)
+	defer app.Close()
+	if fleet {
+		app.ShowFleet()
+	}
 	p := tea.NewProgram(app, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+var (
+	statsWindow time.Duration
+	statsOutput string
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show per-task throughput, wait and run time percentiles",
+	Long: `Show per-task throughput, wait and run time percentiles for a queue,
+computed from procrastinate_events over a recent window.
+
+Wait time runs from a job being deferred (or retried) to it starting;
+run time from starting to succeeding, failing or being aborted.`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().DurationVarP(&statsWindow, "window", "w", time.Hour, "how far back to look (e.g. 15m, 1h, 24h)")
+	statsCmd.Flags().StringVarP(&statsOutput, "output", "o", outputTable, "output format: table, json")
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	if err := checkOutputFormat(statsOutput, outputTable, outputJSON); err != nil {
+		return err
+	}
+	if statsWindow <= 0 {
+		return fmt.Errorf("--window must be positive")
+	}
+
+	_, conn, client, err := openClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	stats, err := db.TaskStats(context.Background(), client, queueOrDefault(conn.DefaultQueue), statsWindow)
+	if err != nil {
+		return fmt.Errorf("computing stats: %w", err)
+	}
+
+	return writeStats(cmd.OutOrStdout(), stats, statsOutput)
+}
+
+// percentilesRecord is the machine-readable shape of db.Percentiles, in seconds.
+type percentilesRecord struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+// statsRecord is the machine-readable shape of db.TaskMetrics. Task is null
+// for the all-tasks total.
+type statsRecord struct {
+	Task        *string            `json:"task"`
+	Finished    int64              `json:"finished"`
+	Failed      int64              `json:"failed"`
+	PerMinute   float64            `json:"per_minute"`
+	WaitSeconds *percentilesRecord `json:"wait_seconds"`
+	RunSeconds  *percentilesRecord `json:"run_seconds"`
+}
+
+func newPercentilesRecord(p *db.Percentiles) *percentilesRecord {
+	if p == nil {
+		return nil
+	}
+	return &percentilesRecord{P50: p.P50.Seconds(), P95: p.P95.Seconds(), P99: p.P99.Seconds()}
+}
+
+// writeStats renders task metrics as JSON or as a table with the total first.
+func writeStats(w io.Writer, stats []db.TaskMetrics, format string) error {
+	if format == outputJSON {
+		records := make([]statsRecord, len(stats))
+		for i, m := range stats {
+			records[i] = statsRecord{
+				Finished:    m.Finished,
+				Failed:      m.Failed,
+				PerMinute:   m.PerMinute,
+				WaitSeconds: newPercentilesRecord(m.Wait),
+				RunSeconds:  newPercentilesRecord(m.Run),
+			}
+			if m.TaskName != "" {
+				records[i].Task = &stats[i].TaskName
+			}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TASK\tFINISHED\tFAILED\tPER MIN\tWAIT p50/p95/p99\tRUN p50/p95/p99")
+	for _, m := range stats {
+		task := m.TaskName
+		if task == "" {
+			task = "(all tasks)"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%s\t%s\n",
+			task, m.Finished, m.Failed, m.PerMinute, formatPercentiles(m.Wait), formatPercentiles(m.Run))
+	}
+	return tw.Flush()
+}
+
+func formatPercentiles(p *db.Percentiles) string {
+	if p == nil {
+		return "-"
+	}
+	round := func(d time.Duration) string {
+		if d >= time.Second {
+			return d.Round(100 * time.Millisecond).String()
+		}
+		return d.Round(time.Millisecond).String()
+	}
+	return round(p.P50) + " / " + round(p.P95) + " / " + round(p.P99)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -16,6 +17,10 @@ type Config struct {
 	Connections     []Connection  `yaml:"connections"`
 }
 
+// EnvironmentProduction marks a connection as a production database.
+// Production connections are read-only unless read_only is explicitly false.
+const EnvironmentProduction = "production"
+
 // Connection represents a named database connection profile.
 // Each connection has its own default queue.
 type Connection struct {
@@ -27,6 +32,22 @@ type Connection struct {
 	Password     string `yaml:"password"`
 	SSLMode      string `yaml:"sslmode"`
 	DefaultQueue string `yaml:"default_queue"`
+	Environment  string `yaml:"environment"` // e.g. production, staging, development
+	ReadOnly     *bool  `yaml:"read_only"`   // nil means "read-only if production"
+}
+
+// IsReadOnly reports whether sessions on this connection must be read-only
+// and mutating actions hidden.
+func (c *Connection) IsReadOnly() bool {
+	if c.ReadOnly != nil {
+		return *c.ReadOnly
+	}
+	return c.IsProduction()
+}
+
+// IsProduction reports whether the connection is marked as production.
+func (c *Connection) IsProduction() bool {
+	return c.Environment == EnvironmentProduction
 }
 
 // Load reads and parses a YAML config file from the given path.
@@ -80,6 +101,7 @@ func (c *Config) Validate() error {
 		if conn.DefaultQueue == "" {
 			c.Connections[i].DefaultQueue = "default"
 		}
+		c.Connections[i].Environment = strings.ToLower(strings.TrimSpace(conn.Environment))
 	}
 
 	if c.PollInterval < 1*time.Second {
@@ -104,11 +126,17 @@ func (c *Config) GetConnection(name string) (*Connection, error) {
 }
 
 // ConnString builds a PostgreSQL connection string for a connection.
+// Read-only connections start every session with default_transaction_read_only,
+// so the server rejects writes even if the role has privileges.
 func ConnString(conn *Connection) string {
-	return fmt.Sprintf(
+	s := fmt.Sprintf(
 		"postgres://%s:%s@%s:%d/%s?sslmode=%s",
 		conn.Username, conn.Password, conn.Host, conn.Port, conn.Database, conn.SSLMode,
 	)
+	if conn.IsReadOnly() {
+		s += "&default_transaction_read_only=on"
+	}
+	return s
 }
 
 // FindConfigPath returns the first config file found in the search order:
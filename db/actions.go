@@ -0,0 +1,304 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RetryJob moves a single failed, cancelled or aborted job back to todo.
+func RetryJob(ctx context.Context, c *Client, id int64) error {
+	retried, err := RetryJobs(ctx, c, []int64{id})
+	if err != nil {
+		return err
+	}
+	if len(retried) == 0 {
+		return fmt.Errorf("job #%d not found or not failed, cancelled or aborted", id)
+	}
+	return nil
+}
+
+// RetryJobs moves failed, cancelled and aborted jobs back to todo in a single
+// transaction, mirroring Procrastinate's retry: attempts is incremented, the
+// job is scheduled for now and a 'retried' event is recorded.
+// Jobs that are missing or in any other status are skipped.
+// Returns the IDs that were actually retried.
+func RetryJobs(ctx context.Context, c *Client, ids []int64) ([]int64, error) {
+	schema := c.schema
+	return transitionJobs(ctx, c, ids, transition{
+		from: []JobStatus{StatusFailed, StatusCancelled, StatusAborted},
+		set: append([]string{
+			"status = 'todo'",
+			"attempts = attempts + 1",
+			"scheduled_at = NOW()",
+		}, schema.resetWorkerState()...),
+		event: "retried",
+	})
+}
+
+// CancelJob cancels a single todo job.
+func CancelJob(ctx context.Context, c *Client, id int64) error {
+	cancelled, err := CancelJobs(ctx, c, []int64{id})
+	if err != nil {
+		return err
+	}
+	if len(cancelled) == 0 {
+		return fmt.Errorf("job #%d not found or not todo", id)
+	}
+	return nil
+}
+
+// CancelJobs moves todo jobs to cancelled and records a 'cancelled' event.
+// Jobs that are missing or no longer todo are skipped.
+// Returns the IDs that were actually cancelled.
+func CancelJobs(ctx context.Context, c *Client, ids []int64) ([]int64, error) {
+	return transitionJobs(ctx, c, ids, transition{
+		from:  []JobStatus{StatusTodo},
+		set:   []string{"status = 'cancelled'"},
+		event: "cancelled",
+	})
+}
+
+// AbortJob requests abortion of a single doing job.
+func AbortJob(ctx context.Context, c *Client, id int64) error {
+	aborting, err := AbortJobs(ctx, c, []int64{id})
+	if err != nil {
+		return err
+	}
+	if len(aborting) == 0 {
+		return fmt.Errorf("job #%d not found or not doing", id)
+	}
+	return nil
+}
+
+// AbortJobs flags doing jobs with abort_requested, moves them to aborting and
+// records an 'abort_requested' event. Schemas without the abort_requested
+// column (2.x) only change the status; schemas without the aborting status
+// only set the flag. The worker running the job is responsible for noticing
+// the request and stopping it.
+// Returns the IDs that were actually flagged.
+func AbortJobs(ctx context.Context, c *Client, ids []int64) ([]int64, error) {
+	schema := c.schema
+
+	var set []string
+	if schema.HasStatus(StatusAborting) {
+		set = append(set, "status = 'aborting'")
+	}
+	if schema.HasAbortRequested {
+		set = append(set, "abort_requested = true")
+	}
+	if len(set) == 0 {
+		return nil, fmt.Errorf("this Procrastinate schema has no way to request an abort")
+	}
+
+	return transitionJobs(ctx, c, ids, transition{
+		from:  []JobStatus{StatusDoing},
+		set:   set,
+		event: "abort_requested",
+	})
+}
+
+// RequeueJobs resets orphaned doing and todo jobs to todo, detaching them
+// from their worker and scheduling them for now. Only jobs that are still
+// orphaned (see ListOrphanedJobs) change, so a job a live worker picked up
+// meanwhile is left alone. A 'deferred_for_retry' event is recorded for
+// requeued doing jobs; stale todo jobs never started, so they get none.
+// Returns the IDs that were actually requeued.
+func RequeueJobs(ctx context.Context, c *Client, ids []int64, threshold time.Duration) ([]int64, error) {
+	schema := c.schema
+	return transitionJobs(ctx, c, ids, transition{
+		from:      []JobStatus{StatusDoing, StatusTodo},
+		set:       append([]string{"status = 'todo'", "scheduled_at = NOW()"}, schema.resetWorkerState()...),
+		where:     orphanedCondition(schema, "$3"),
+		args:      []any{threshold.String()},
+		event:     "deferred_for_retry",
+		eventFrom: []JobStatus{StatusDoing},
+	})
+}
+
+// FailJobs marks orphaned doing and todo jobs as failed. Doing jobs get a
+// 'failed' event; for todo jobs Procrastinate's status trigger already
+// records one ('cancelled'), so none is added.
+// Returns the IDs that were actually failed.
+func FailJobs(ctx context.Context, c *Client, ids []int64, threshold time.Duration) ([]int64, error) {
+	return transitionJobs(ctx, c, ids, transition{
+		from:      []JobStatus{StatusDoing, StatusTodo},
+		set:       []string{"status = 'failed'"},
+		where:     orphanedCondition(c.schema, "$3"),
+		args:      []any{threshold.String()},
+		event:     "failed",
+		eventFrom: []JobStatus{StatusDoing},
+	})
+}
+
+// ForceCancelJobs cancels orphaned doing and todo jobs and records a
+// 'cancelled' event. Unlike CancelJobs it also cancels doing jobs, but only
+// while they are still orphaned, i.e. no live worker is running them.
+// Returns the IDs that were actually cancelled.
+func ForceCancelJobs(ctx context.Context, c *Client, ids []int64, threshold time.Duration) ([]int64, error) {
+	schema := c.schema
+	set := []string{"status = 'cancelled'"}
+	if schema.HasWorkerID {
+		set = append(set, "worker_id = NULL")
+	}
+	return transitionJobs(ctx, c, ids, transition{
+		from:  []JobStatus{StatusDoing, StatusTodo},
+		set:   set,
+		where: orphanedCondition(schema, "$3"),
+		args:  []any{threshold.String()},
+		event: "cancelled",
+	})
+}
+
+// transition describes a status change applied by transitionJobs.
+type transition struct {
+	from  []JobStatus // only jobs currently in one of these statuses change
+	set   []string    // SET assignments
+	where string      // extra condition on the job row "j", empty for none
+	args  []any       // parameters of where, from $3
+	event string      // event type recorded for each changed job
+
+	// eventFrom limits the event to jobs that were in one of these statuses;
+	// nil records it for every changed job.
+	eventFrom []JobStatus
+}
+
+// resetWorkerState returns the SET assignments that detach a job from its
+// worker and clear any abort request, for the columns this schema has.
+func (s *Schema) resetWorkerState() []string {
+	var set []string
+	if s.HasWorkerID {
+		set = append(set, "worker_id = NULL")
+	}
+	if s.HasAbortRequested {
+		set = append(set, "abort_requested = false")
+	}
+	return set
+}
+
+// transitionJobs applies a status change to the given jobs and records the
+// transition's event for every changed job, all in one transaction.
+// Returns the IDs that were actually changed.
+func transitionJobs(ctx context.Context, c *Client, ids []int64, t transition) ([]int64, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	from := make([]string, len(t.from))
+	for i, st := range t.from {
+		from[i] = string(st)
+	}
+
+	tx, err := c.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the rows first so the statuses read here are the ones updated.
+	before := make(map[int64]JobStatus, len(ids))
+	if t.eventFrom != nil {
+		rows, err := tx.Query(ctx, `
+			SELECT id, status::text
+			FROM procrastinate_jobs
+			WHERE id = ANY($1)
+			FOR UPDATE`, ids)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id int64
+			var status JobStatus
+			if err := rows.Scan(&id, &status); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			before[id] = status
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	where := ""
+	if t.where != "" {
+		where = "AND " + t.where
+	}
+	rows, err := tx.Query(ctx, fmt.Sprintf(`
+		UPDATE procrastinate_jobs AS j
+		SET %s
+		WHERE j.id = ANY($1)
+		  AND j.status::text = ANY($2)
+		  %s
+		RETURNING j.id`, strings.Join(t.set, ", "), where), append([]any{ids, from}, t.args...)...)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := scanIDs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.schema.HasEventType(t.event) {
+		evented := changed
+		if t.eventFrom != nil {
+			evented = nil
+			for _, id := range changed {
+				if slices.Contains(t.eventFrom, before[id]) {
+					evented = append(evented, id)
+				}
+			}
+		}
+		if err := recordEvents(ctx, tx, evented, t.event); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// recordEvents inserts an event of the given type for each job.
+// Procrastinate's own status trigger may already have written the same event
+// in this transaction (its "at" defaults to NOW()), so those are skipped.
+func recordEvents(ctx context.Context, tx pgx.Tx, ids []int64, eventType string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO procrastinate_events (job_id, type)
+		SELECT t.job_id, $2::procrastinate_job_event_type
+		FROM unnest($1::bigint[]) AS t(job_id)
+		WHERE NOT EXISTS (
+		  SELECT 1 FROM procrastinate_events e
+		  WHERE e.job_id = t.job_id
+		    AND e.type = $2::procrastinate_job_event_type
+		    AND e.at = NOW()
+		)`, ids, eventType)
+	if err != nil {
+		return fmt.Errorf("recording %s events: %w", eventType, err)
+	}
+	return nil
+}
+
+// scanIDs is a helper that scans a single bigint column into a slice.
+func scanIDs(rows pgx.Rows) ([]int64, error) {
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
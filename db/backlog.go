@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Backlog trend sampling: one step per minute over the last half hour.
+const (
+	backlogWindow = 30 * time.Minute
+	backlogStep   = time.Minute
+)
+
+// BacklogTrend is the todo backlog of a queue over the recent past,
+// reconstructed from procrastinate_events by walking back from the current
+// todo count.
+type BacklogTrend struct {
+	Todo     int64         // current todo count
+	Series   []int64       // todo count at the end of each step, oldest first
+	Step     time.Duration // time covered by each element of Series
+	Enqueued int64         // jobs that entered todo during the window
+	Dequeued int64         // jobs that left todo (started or cancelled)
+}
+
+// Window returns the time span covered by the trend.
+func (t *BacklogTrend) Window() time.Duration {
+	return time.Duration(len(t.Series)) * t.Step
+}
+
+// EnqueueRate returns the jobs entering the backlog per minute.
+func (t *BacklogTrend) EnqueueRate() float64 {
+	return float64(t.Enqueued) / t.Window().Minutes()
+}
+
+// DequeueRate returns the jobs leaving the backlog per minute.
+func (t *BacklogTrend) DequeueRate() float64 {
+	return float64(t.Dequeued) / t.Window().Minutes()
+}
+
+// DrainTime estimates how long the backlog takes to empty at the current
+// rates. ok is false when the backlog is not shrinking.
+func (t *BacklogTrend) DrainTime() (d time.Duration, ok bool) {
+	net := t.DequeueRate() - t.EnqueueRate()
+	if net <= 0 {
+		return 0, false
+	}
+	return time.Duration(float64(t.Todo) / net * float64(time.Minute)), true
+}
+
+// Backlog returns the queue's todo backlog trend over the last half hour.
+// Jobs enter the backlog when deferred or retried and leave it when started
+// or cancelled.
+func Backlog(ctx context.Context, c *Client, queue string) (*BacklogTrend, error) {
+	if !c.schema.HasEvents {
+		return nil, ErrNoEvents
+	}
+
+	t := BacklogTrend{Step: backlogStep, Series: make([]int64, int(backlogWindow/backlogStep))}
+	err := c.pool.QueryRow(ctx, fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM procrastinate_jobs
+		WHERE status = 'todo'
+		  AND %s`, queueMatch("queue_name", queue, 1)), queue).Scan(&t.Todo)
+	if err != nil {
+		return nil, err
+	}
+
+	fromID, err := c.eventFloor(ctx, backlogWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 0 is the most recent one.
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(`
+		SELECT floor(EXTRACT(EPOCH FROM NOW() - e.at) / $3)::int AS step,
+		       COUNT(*) FILTER (WHERE e.type::text IN ('deferred', 'deferred_for_retry', 'retried')),
+		       COUNT(*) FILTER (WHERE e.type::text IN ('started', 'cancelled'))
+		FROM procrastinate_events e
+		JOIN procrastinate_jobs j ON j.id = e.job_id
+		WHERE e.id >= $4
+		  AND e.at > NOW() - $2::interval
+		  AND %s
+		GROUP BY step`, queueMatch("j.queue_name", queue, 1)),
+		queue, backlogWindow.String(), backlogStep.Seconds(), fromID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	steps := len(t.Series)
+	delta := make([]int64, steps)
+	for rows.Next() {
+		var step int
+		var in, out int64
+		if err := rows.Scan(&step, &in, &out); err != nil {
+			return nil, err
+		}
+		if step < 0 || step >= steps {
+			continue
+		}
+		delta[step] = in - out
+		t.Enqueued += in
+		t.Dequeued += out
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	t.Series = backlogSeries(t.Todo, delta)
+	return &t, nil
+}
+
+// backlogSeries rebuilds the backlog at the end of each step, oldest first,
+// from the current todo count and each step's net change, newest first.
+func backlogSeries(todo int64, delta []int64) []int64 {
+	series := make([]int64, len(delta))
+	for step := range delta {
+		series[len(delta)-1-step] = max(todo, 0)
+		todo -= delta[step]
+	}
+	return series
+}
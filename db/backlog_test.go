@@ -0,0 +1,83 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBacklogRates(t *testing.T) {
+	tests := []struct {
+		name            string
+		trend           BacklogTrend
+		wantWindow      time.Duration
+		wantIn, wantOut float64
+		wantDrain       time.Duration
+		wantDrainOK     bool
+	}{
+		{
+			name:        "shrinking",
+			trend:       BacklogTrend{Todo: 120, Enqueued: 300, Dequeued: 600, Step: time.Minute, Series: make([]int64, 30)},
+			wantWindow:  30 * time.Minute,
+			wantIn:      10,
+			wantOut:     20,
+			wantDrain:   12 * time.Minute,
+			wantDrainOK: true,
+		},
+		{
+			name:       "growing",
+			trend:      BacklogTrend{Todo: 50, Enqueued: 90, Dequeued: 30, Step: time.Minute, Series: make([]int64, 30)},
+			wantWindow: 30 * time.Minute,
+			wantIn:     3,
+			wantOut:    1,
+		},
+		{
+			name:       "steady",
+			trend:      BacklogTrend{Todo: 5, Enqueued: 40, Dequeued: 40, Step: 30 * time.Second, Series: make([]int64, 4)},
+			wantWindow: 2 * time.Minute,
+			wantIn:     20,
+			wantOut:    20,
+		},
+		{
+			name:        "empty and draining",
+			trend:       BacklogTrend{Todo: 0, Dequeued: 30, Step: time.Minute, Series: make([]int64, 30)},
+			wantWindow:  30 * time.Minute,
+			wantOut:     1,
+			wantDrainOK: true,
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.trend.Window(); got != tt.wantWindow {
+			t.Errorf("%s: Window() = %v, want %v", tt.name, got, tt.wantWindow)
+		}
+		if got := tt.trend.EnqueueRate(); got != tt.wantIn {
+			t.Errorf("%s: EnqueueRate() = %v, want %v", tt.name, got, tt.wantIn)
+		}
+		if got := tt.trend.DequeueRate(); got != tt.wantOut {
+			t.Errorf("%s: DequeueRate() = %v, want %v", tt.name, got, tt.wantOut)
+		}
+		d, ok := tt.trend.DrainTime()
+		if d != tt.wantDrain || ok != tt.wantDrainOK {
+			t.Errorf("%s: DrainTime() = %v, %v; want %v, %v", tt.name, d, ok, tt.wantDrain, tt.wantDrainOK)
+		}
+	}
+}
+
+func TestBacklogSeries(t *testing.T) {
+	tests := []struct {
+		todo  int64
+		delta []int64
+		want  []int64
+	}{
+		{10, []int64{0, 0, 0}, []int64{10, 10, 10}},
+		{10, []int64{2, -3, 5}, []int64{11, 8, 10}},
+		// Events older than the window can make earlier steps look negative.
+		{1, []int64{4, 0}, []int64{0, 1}},
+		{0, nil, []int64{}},
+	}
+	for _, tt := range tests {
+		if got := backlogSeries(tt.todo, tt.delta); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("backlogSeries(%d, %v) = %v, want %v", tt.todo, tt.delta, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package db
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -12,21 +14,31 @@ import (
 type Client struct {
 	pool    *pgxpool.Pool
 	connStr string
+	schema  *Schema
+
+	mu          sync.Mutex
+	eventFloors map[time.Duration]int64 // see eventFloor
 }
 
-// NewClient creates a new database client with a connection pool.
-func NewClient(connStr string) (*Client, error) {
-	pool, err := pgxpool.New(context.Background(), connStr)
+// NewClient creates a new database client with a connection pool. ctx
+// bounds connecting and detecting the schema.
+func NewClient(ctx context.Context, connStr string) (*Client, error) {
+	pool, err := pgxpool.New(ctx, connStr)
 	if err != nil {
 		return nil, fmt.Errorf("creating connection pool: %w", err)
 	}
 
-	if err := pool.Ping(context.Background()); err != nil {
+	if err := pool.Ping(ctx); err != nil {
 		pool.Close()
 		return nil, fmt.Errorf("connecting to database: %w", err)
 	}
 
-	return &Client{pool: pool, connStr: connStr}, nil
+	schema, err := DetectSchema(ctx, pool)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return &Client{pool: pool, connStr: connStr, schema: schema}, nil
 }
 
 // Close shuts down the connection pool.
@@ -36,11 +48,54 @@ func (c *Client) Close() {
 	}
 }
 
+// eventFloor returns an event id at or below the first procrastinate_events
+// row within the window. procrastinate_events has no index on "at", so
+// queries over recent events bound their scan by id instead. Windows only
+// move forward, so each call walks the primary key from the floor it found
+// last time rather than from the start of the table.
+func (c *Client) eventFloor(ctx context.Context, window time.Duration) (int64, error) {
+	c.mu.Lock()
+	floor := c.eventFloors[window]
+	c.mu.Unlock()
+
+	err := c.pool.QueryRow(ctx, `
+		SELECT COALESCE(
+		  (SELECT min(id) FROM procrastinate_events WHERE id >= $1 AND at > NOW() - $2::interval),
+		  (SELECT COALESCE(max(id), 0) + 1 FROM procrastinate_events))`,
+		floor, window.String()).Scan(&floor)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	if c.eventFloors == nil {
+		c.eventFloors = make(map[time.Duration]int64)
+	}
+	c.eventFloors[window] = max(c.eventFloors[window], floor)
+	c.mu.Unlock()
+	return floor, nil
+}
+
+// Schema returns the Procrastinate schema detected when connecting.
+func (c *Client) Schema() *Schema {
+	return c.schema
+}
+
 // Pool returns the underlying connection pool for running queries.
 func (c *Client) Pool() *pgxpool.Pool {
 	return c.pool
 }
 
+// NewListener creates a listener for a queue using the notify channel names
+// of the detected schema.
+func (c *Client) NewListener(queue string) *Listener {
+	l := NewListener(c.NewListenerConn, queue)
+	l.queueChannelPrefix = c.schema.QueueChannelPrefix
+	l.anyQueueChannel = c.schema.AnyQueueChannel
+	l.jsonPayloads = c.schema.NotifyHasJobID
+	return l
+}
+
 // NewListenerConn creates a dedicated connection for LISTEN/NOTIFY.
 // This is separate from the pool because WaitForNotification blocks.
 func (c *Client) NewListenerConn(ctx context.Context) (*pgx.Conn, error) {
@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Procrastinate's defer functions, newest first.
+const (
+	deferJobsV1        = "procrastinate_defer_jobs_v1"
+	deferJob           = "procrastinate_defer_job"
+	deferJobNoPriority = "procrastinate_defer_job (without priority)"
+)
+
+// uniqueViolation is the SQLSTATE of a unique index conflict.
+const uniqueViolation = "23505"
+
+// DeferRequest describes a job to enqueue.
+type DeferRequest struct {
+	TaskName     string
+	Queue        string
+	Args         json.RawMessage // a JSON object; empty means {}
+	Priority     int
+	Lock         *string
+	QueueingLock *string
+	ScheduledAt  *time.Time // nil runs the job as soon as possible
+}
+
+// QueueingLockError reports a defer rejected because a todo job already
+// holds the queueing lock.
+type QueueingLockError struct {
+	QueueingLock string
+	HolderID     *int64 // the todo job holding the lock, when it could be found
+}
+
+func (e *QueueingLockError) Error() string {
+	if e.HolderID != nil {
+		return fmt.Sprintf("queueing lock %q is already held by todo job #%d; it must start or be cancelled before another job can use the lock",
+			e.QueueingLock, *e.HolderID)
+	}
+	return fmt.Sprintf("queueing lock %q is already held by a todo job", e.QueueingLock)
+}
+
+// DeferJob enqueues a job through Procrastinate's own defer function, so
+// triggers, events and notifications behave as for jobs deferred by the
+// application. Returns the new job's ID, or a *QueueingLockError when the
+// queueing lock is taken.
+func DeferJob(ctx context.Context, c *Client, req DeferRequest) (int64, error) {
+	if strings.TrimSpace(req.TaskName) == "" {
+		return 0, fmt.Errorf("task name is required")
+	}
+	if strings.TrimSpace(req.Queue) == "" || req.Queue == AllQueues {
+		return 0, fmt.Errorf("queue is required")
+	}
+	args := req.Args
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(args, &obj); err != nil || obj == nil {
+		return 0, fmt.Errorf("args must be a JSON object")
+	}
+
+	values := []any{req.Queue, req.TaskName, req.Priority, req.Lock, req.QueueingLock, string(args), req.ScheduledAt}
+
+	var query string
+	switch fn := c.schema.DeferFunction; fn {
+	case deferJobsV1:
+		query = `
+			SELECT (procrastinate_defer_jobs_v1(ARRAY[
+			  ROW($1::varchar, $2::varchar, $3::integer, $4::text, $5::text, $6::jsonb, $7::timestamptz)
+			  ::procrastinate_job_to_defer_v1]))[1]`
+	case deferJob:
+		query = `SELECT procrastinate_defer_job($1::varchar, $2::varchar, $3::integer, $4::text, $5::text, $6::jsonb, $7::timestamptz)`
+	case deferJobNoPriority:
+		if req.Priority != 0 {
+			return 0, fmt.Errorf("this Procrastinate schema does not support job priorities")
+		}
+		query = `SELECT procrastinate_defer_job($1::varchar, $2::varchar, $3::text, $4::text, $5::jsonb, $6::timestamptz)`
+		values = slices.Delete(values, 2, 3)
+	default:
+		return 0, fmt.Errorf("this Procrastinate schema has no defer function")
+	}
+
+	var id int64
+	err := c.pool.QueryRow(ctx, query, values...).Scan(&id)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation &&
+		strings.Contains(pgErr.ConstraintName, "queueing_lock") && req.QueueingLock != nil {
+		return 0, queueingLockError(ctx, c, *req.QueueingLock)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// queueingLockError looks up the todo job holding a queueing lock.
+func queueingLockError(ctx context.Context, c *Client, lock string) error {
+	e := &QueueingLockError{QueueingLock: lock}
+	var id int64
+	err := c.pool.QueryRow(ctx, `
+		SELECT id
+		FROM procrastinate_jobs
+		WHERE queueing_lock = $1
+		  AND status = 'todo'
+		LIMIT 1`, lock).Scan(&id)
+	if err == nil {
+		e.HolderID = &id
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	return e
+}
+
+// ParseSchedule parses when to run a deferred job: a delay such as 10m,
+// +1h30m or 2d from now, or a time (2006-01-02, 2006-01-02T15:04 or
+// RFC 3339, in local time unless a zone is given). An empty string
+// returns nil, to run now. Times before now are rejected, since they most
+// likely mean a typo and the job would just run now.
+func ParseSchedule(s string, now time.Time) (*time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	rel := s
+	if s[0] != '+' && s[0] != '-' {
+		rel = "+" + s
+	}
+	var t time.Time
+	if d, err := parseRelative(rel); err == nil {
+		t = now.Add(d)
+	} else if t, err = parseQueryTime(s); err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: use a delay like 10m or a time like 2006-01-02T15:04", s)
+	}
+	if t.Before(now) {
+		return nil, fmt.Errorf("schedule %q is in the past: leave it empty to run now", s)
+	}
+	return &t, nil
+}
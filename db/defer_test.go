@@ -0,0 +1,53 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		in      string
+		want    time.Time // zero for nil
+		wantErr string
+	}{
+		{in: ""},
+		{in: "   "},
+		{in: "10m", want: now.Add(10 * time.Minute)},
+		{in: "+1h30m", want: now.Add(90 * time.Minute)},
+		{in: "2d", want: now.Add(48 * time.Hour)},
+		{in: " +0.5d ", want: now.Add(12 * time.Hour)},
+		{in: "2026-03-02T15:04", want: time.Date(2026, time.March, 2, 15, 4, 0, 0, time.Local)},
+		{in: "2026-03-05", want: time.Date(2026, time.March, 5, 0, 0, 0, 0, time.Local)},
+		{in: "2026-03-02T10:00:00Z", want: time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)},
+		{in: "-1h", wantErr: "in the past"},
+		{in: "2026-02-28T09:00", wantErr: "in the past"},
+		{in: "tomorrow", wantErr: "invalid schedule"},
+		{in: "10x", wantErr: "invalid schedule"},
+		{in: "+-1h", wantErr: "invalid schedule"},
+	}
+	for _, tt := range tests {
+		got, err := ParseSchedule(tt.in, now)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseSchedule(%q) error = %v, want it to contain %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSchedule(%q): %v", tt.in, err)
+			continue
+		}
+		if tt.want.IsZero() {
+			if got != nil {
+				t.Errorf("ParseSchedule(%q) = %v, want nil", tt.in, *got)
+			}
+			continue
+		}
+		if got == nil || !got.Equal(tt.want) {
+			t.Errorf("ParseSchedule(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
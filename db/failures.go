@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// failureGroupJobs caps the job IDs kept per failure group.
+const failureGroupJobs = 50
+
+// FailureGroup counts a task's failed jobs and jobs waiting to be retried
+// with the same number of attempts, alongside the jobs that succeeded after
+// as many attempts.
+type FailureGroup struct {
+	TaskName  string
+	Attempts  int
+	Failed    int64
+	Pending   int64   // todo jobs waiting for another attempt
+	Recovered int64   // succeeded jobs with the same attempts, when retried at least once
+	JobIDs    []int64 // most recent failed and pending jobs, newest first
+}
+
+// ListFailureGroups groups the queue's failed jobs, its todo jobs waiting
+// to be retried and its succeeded jobs that needed retries, by task and
+// attempts. Groups are ordered by task, then attempts.
+func ListFailureGroups(ctx context.Context, c *Client, queue string) ([]FailureGroup, error) {
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(`
+		SELECT task_name, attempts,
+		       COUNT(*) FILTER (WHERE status = 'failed'),
+		       COUNT(*) FILTER (WHERE status = 'todo'),
+		       COUNT(*) FILTER (WHERE status = 'succeeded'),
+		       COALESCE((array_agg(id ORDER BY id DESC)
+		                 FILTER (WHERE status IN ('failed', 'todo')))[1:$2], '{}')
+		FROM procrastinate_jobs
+		WHERE (status = 'failed'
+		       OR (status = 'todo' AND attempts > 0)
+		       OR (status = 'succeeded' AND attempts > 1))
+		  AND %s
+		GROUP BY task_name, attempts
+		ORDER BY task_name, attempts`, queueMatch("queue_name", queue, 1)), queue, failureGroupJobs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []FailureGroup
+	for rows.Next() {
+		var g FailureGroup
+		if err := rows.Scan(&g.TaskName, &g.Attempts, &g.Failed, &g.Pending, &g.Recovered, &g.JobIDs); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
+// Attempt is one run of a job, rebuilt from its events.
+type Attempt struct {
+	QueuedAt  *time.Time // deferred, deferred_for_retry or retried event
+	StartedAt *time.Time
+	EndedAt   *time.Time
+	Outcome   string // event type that ended the attempt; empty while pending or running
+}
+
+// Pending reports whether the attempt is queued but has not started.
+func (a Attempt) Pending() bool {
+	return a.StartedAt == nil && a.Outcome == ""
+}
+
+// RetryChain rebuilds a job's attempts from its events, which must be in
+// chronological order. A deferred_for_retry event both ends an attempt and
+// queues the next one; a retried event queues a new attempt after a final
+// failure.
+func RetryChain(events []JobEvent) []Attempt {
+	var chain []Attempt
+	current := func() *Attempt {
+		if len(chain) == 0 {
+			return nil
+		}
+		return &chain[len(chain)-1]
+	}
+
+	for _, e := range events {
+		at := e.At
+		switch e.Type {
+		case "deferred", "deferred_for_retry", "retried":
+			if c := current(); c != nil && c.StartedAt != nil && c.Outcome == "" {
+				c.EndedAt = &at
+				c.Outcome = e.Type
+			}
+			if c := current(); c == nil || !c.Pending() {
+				chain = append(chain, Attempt{QueuedAt: &at})
+			}
+		case "started":
+			if c := current(); c == nil || !c.Pending() {
+				chain = append(chain, Attempt{})
+			}
+			current().StartedAt = &at
+		case "succeeded", "failed", "aborted", "cancelled":
+			if c := current(); c != nil && c.Outcome == "" {
+				c.EndedAt = &at
+				c.Outcome = e.Type
+			}
+		}
+	}
+	return chain
+}
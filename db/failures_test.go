@@ -0,0 +1,103 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryChain(t *testing.T) {
+	base := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	type ev struct {
+		typ string
+		min int // minutes after base
+	}
+	// want fields are minutes after base; -1 means unset.
+	type attempt struct {
+		queued, started, ended int
+		outcome                string
+		pending                bool
+	}
+	tests := []struct {
+		name   string
+		events []ev
+		want   []attempt
+	}{
+		{
+			name: "no events",
+		},
+		{
+			name:   "single run",
+			events: []ev{{"deferred", 0}, {"started", 1}, {"succeeded", 3}},
+			want:   []attempt{{0, 1, 3, "succeeded", false}},
+		},
+		{
+			name: "deferred_for_retry cycles",
+			events: []ev{
+				{"deferred", 0}, {"started", 1}, {"deferred_for_retry", 2},
+				{"started", 5}, {"deferred_for_retry", 6},
+				{"started", 9}, {"failed", 10},
+			},
+			want: []attempt{
+				{0, 1, 2, "deferred_for_retry", false},
+				{2, 5, 6, "deferred_for_retry", false},
+				{6, 9, 10, "failed", false},
+			},
+		},
+		{
+			name: "manual retry after a final failure",
+			events: []ev{
+				{"deferred", 0}, {"started", 1}, {"failed", 2},
+				{"retried", 10}, {"started", 11}, {"succeeded", 12},
+			},
+			want: []attempt{
+				{0, 1, 2, "failed", false},
+				{10, 11, 12, "succeeded", false},
+			},
+		},
+		{
+			name:   "trailing attempt still running",
+			events: []ev{{"deferred", 0}, {"started", 1}, {"deferred_for_retry", 2}, {"started", 4}},
+			want: []attempt{
+				{0, 1, 2, "deferred_for_retry", false},
+				{2, 4, -1, "", false},
+			},
+		},
+		{
+			name:   "retry pending",
+			events: []ev{{"deferred", 0}, {"started", 1}, {"deferred_for_retry", 2}},
+			want: []attempt{
+				{0, 1, 2, "deferred_for_retry", false},
+				{2, -1, -1, "", true},
+			},
+		},
+		{
+			name:   "start without a queueing event",
+			events: []ev{{"started", 1}, {"aborted", 2}},
+			want:   []attempt{{-1, 1, 2, "aborted", false}},
+		},
+	}
+
+	at := func(tp *time.Time) int {
+		if tp == nil {
+			return -1
+		}
+		return int(tp.Sub(base) / time.Minute)
+	}
+	for _, tt := range tests {
+		var events []JobEvent
+		for i, e := range tt.events {
+			events = append(events, JobEvent{ID: int64(i + 1), JobID: 1, Type: e.typ, At: base.Add(time.Duration(e.min) * time.Minute)})
+		}
+		chain := RetryChain(events)
+		if len(chain) != len(tt.want) {
+			t.Errorf("%s: got %d attempts, want %d", tt.name, len(chain), len(tt.want))
+			continue
+		}
+		for i, a := range chain {
+			got := attempt{at(a.QueuedAt), at(a.StartedAt), at(a.EndedAt), a.Outcome, a.Pending()}
+			if got != tt.want[i] {
+				t.Errorf("%s: attempt %d = %+v, want %+v", tt.name, i+1, got, tt.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JobFilter narrows the jobs returned by ListJobsFiltered and ListJobsPage.
+// Zero-valued fields match everything.
+type JobFilter struct {
+	Queue  string
+	Status string
+	Task   string // glob pattern: '*' matches any run of characters, '?' a single one
+	Worker *int64 // only jobs assigned to this worker
+	Where  *Query // filter expression; nil matches everything
+}
+
+// JobCursor marks the last job of a page for keyset pagination.
+type JobCursor struct {
+	ID   int64
+	Rank int // statusRank of the job, used when no status filter is set
+}
+
+// CursorAfter returns the cursor that continues a listing after job.
+func CursorAfter(job Job) JobCursor {
+	return JobCursor{ID: job.ID, Rank: statusRank(job.Status)}
+}
+
+// statusRank mirrors statusRankSQL: doing first, todo second, then the rest.
+func statusRank(s JobStatus) int {
+	switch s {
+	case StatusDoing:
+		return 0
+	case StatusTodo:
+		return 1
+	default:
+		return 2
+	}
+}
+
+const statusRankSQL = `CASE status WHEN 'doing' THEN 0 WHEN 'todo' THEN 1 ELSE 2 END`
+
+// statusBuckets are the conditions selecting each statusRank, in listing
+// order. They are literals so the planner can match them against
+// Procrastinate's partial index on todo and doing jobs.
+var statusBuckets = []string{
+	"status = 'doing'",
+	"status = 'todo'",
+	"status NOT IN ('doing', 'todo')",
+}
+
+// ListJobsPage returns up to limit jobs matching the filter, in the same order
+// as ListJobsFiltered, starting after the given cursor (nil for the first
+// page). Unlike OFFSET, the cost does not grow with the page depth. Without
+// a status filter each status bucket is read by id in turn, so every query
+// can walk an index instead of sorting the table.
+func ListJobsPage(ctx context.Context, c *Client, filter JobFilter, after *JobCursor, limit int) ([]Job, error) {
+	if filter.Status != "" {
+		var afterID *int64
+		if after != nil {
+			afterID = &after.ID
+		}
+		return listJobsByID(ctx, c, filter, "", afterID, limit)
+	}
+
+	rank := 0
+	if after != nil {
+		rank = after.Rank
+	}
+	var jobs []Job
+	for ; rank < len(statusBuckets) && len(jobs) < limit; rank++ {
+		var afterID *int64
+		if after != nil && rank == after.Rank {
+			afterID = &after.ID
+		}
+		page, err := listJobsByID(ctx, c, filter, statusBuckets[rank], afterID, limit-len(jobs))
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, page...)
+	}
+	return jobs, nil
+}
+
+// listJobsByID returns up to limit jobs matching the filter and the extra
+// condition, newest first, with an id below afterID when set.
+func listJobsByID(ctx context.Context, c *Client, filter JobFilter, cond string, afterID *int64, limit int) ([]Job, error) {
+	var args sqlArgs
+	conds := filter.conditions(&args)
+	if cond != "" {
+		conds = append(conds, cond)
+	}
+	if afterID != nil {
+		conds = append(conds, "id < "+args.add(*afterID))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM procrastinate_jobs
+		%s
+		ORDER BY id DESC
+		LIMIT %s`, c.schema.jobColumns(""), whereClause(conds), args.add(limit))
+
+	rows, err := c.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanJobs(rows)
+}
+
+// SortsAfter reports whether job comes after the cursor in the filter's
+// listing order.
+func (f JobFilter) SortsAfter(job Job, cursor JobCursor) bool {
+	if f.Status != "" {
+		return job.ID < cursor.ID
+	}
+	rank := statusRank(job.Status)
+	return rank > cursor.Rank || (rank == cursor.Rank && job.ID < cursor.ID)
+}
+
+// EstimateJobs returns the planner's row estimate for the filter. It is
+// instant on large tables, where an exact COUNT(*) would not be.
+func EstimateJobs(ctx context.Context, c *Client, filter JobFilter) (int64, error) {
+	var args sqlArgs
+	conds := filter.conditions(&args)
+
+	var raw []byte
+	err := c.pool.QueryRow(ctx,
+		"EXPLAIN (FORMAT JSON) SELECT 1 FROM procrastinate_jobs "+whereClause(conds),
+		args...).Scan(&raw)
+	if err != nil {
+		return 0, err
+	}
+
+	var plan []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &plan); err != nil || len(plan) == 0 {
+		return 0, fmt.Errorf("parsing plan estimate: %w", err)
+	}
+	return int64(plan[0].Plan.Rows), nil
+}
+
+// conditions returns the filter's WHERE conditions, adding their
+// parameters to args.
+func (f JobFilter) conditions(args *sqlArgs) []string {
+	var conds []string
+	if f.Queue != "" {
+		conds = append(conds, "queue_name = "+args.add(f.Queue))
+	}
+	if f.Status != "" {
+		conds = append(conds, "status = "+args.add(f.Status))
+	}
+	if f.Task != "" {
+		conds = append(conds, "task_name LIKE "+args.add(globToLike(f.Task)))
+	}
+	if f.Worker != nil {
+		conds = append(conds, "worker_id = "+args.add(*f.Worker))
+	}
+	return append(conds, f.Where.conditions(args)...)
+}
+
+// orderBy returns the listing order: by id when a status is selected,
+// otherwise doing first, todo second, then everything else, newest first.
+func (f JobFilter) orderBy() string {
+	if f.Status != "" {
+		return "id DESC"
+	}
+	return statusRankSQL + ", id DESC"
+}
+
+// queueMatch returns a condition matching column against the queue in
+// parameter $n. For AllQueues the parameter is still referenced so the
+// query's parameter numbering does not change.
+func queueMatch(column, queue string, n int) string {
+	if queue == AllQueues {
+		return fmt.Sprintf("$%d::text IS NOT NULL", n)
+	}
+	return fmt.Sprintf("%s = $%d", column, n)
+}
+
+// sqlArgs collects positional query parameters.
+type sqlArgs []any
+
+// add appends a parameter and returns its placeholder.
+func (a *sqlArgs) add(v any) string {
+	*a = append(*a, v)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+func whereClause(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(conds, " AND ")
+}
+
+// globToLike converts a shell-style glob into a LIKE pattern,
+// escaping LIKE's own wildcards.
+func globToLike(glob string) string {
+	var b strings.Builder
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteRune('%')
+		case '?':
+			b.WriteRune('_')
+		case '%', '_', '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
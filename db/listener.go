@@ -4,52 +4,112 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"sync"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
 
+// Reconnect backoff for the listener connection.
+const (
+	listenerInitialBackoff = 1 * time.Second
+	listenerMaxBackoff     = 30 * time.Second
+
+	// After this many failed reconnect attempts the listener reports itself
+	// as degraded; callers should rely on polling until it recovers.
+	listenerDegradeAfter = 3
+)
+
+// ListenerState describes the health of the LISTEN connection.
+type ListenerState int
+
+const (
+	ListenerConnected ListenerState = iota
+	ListenerReconnecting
+	ListenerDegraded
+)
+
+func (s ListenerState) String() string {
+	switch s {
+	case ListenerConnected:
+		return "connected"
+	case ListenerReconnecting:
+		return "reconnecting"
+	case ListenerDegraded:
+		return "degraded"
+	default:
+		return "unknown"
+	}
+}
+
 // Listener manages a dedicated PostgreSQL connection for LISTEN/NOTIFY.
+// When the connection drops it reconnects with exponential backoff and
+// re-issues its LISTENs. A state change back to ListenerConnected means
+// notifications may have been missed, so consumers should resync.
 type Listener struct {
-	conn     *pgx.Conn
-	queue    string
+	connect  func(context.Context) (*pgx.Conn, error)
+	conn     *pgx.Conn // owned by the loop goroutine once started
 	notifyCh chan Notification
+	stateCh  chan ListenerState
 	cancel   context.CancelFunc
+	done     chan struct{}
+
+	// Channel naming and payload format; see Schema.
+	queueChannelPrefix string
+	anyQueueChannel    string
+	jsonPayloads       bool
+
+	mu          sync.Mutex
+	queue       string
+	resubscribe bool               // queue changed; re-issue LISTENs
+	cancelWait  context.CancelFunc // interrupts the current WaitForNotification
 }
 
 // NewListener creates a listener bound to a queue.
-// The provided connection must be a raw pgx.Conn (not from a pool).
-func NewListener(conn *pgx.Conn, queue string) *Listener {
+// connect must open a raw pgx.Conn (not from a pool); it is called once on
+// Start and again on every reconnect.
+func NewListener(connect func(context.Context) (*pgx.Conn, error), queue string) *Listener {
 	return &Listener{
-		conn:     conn,
+		connect:  connect,
 		queue:    queue,
 		notifyCh: make(chan Notification, 64),
+		stateCh:  make(chan ListenerState, 1),
+		done:     make(chan struct{}),
+
+		queueChannelPrefix: defaultSchema.QueueChannelPrefix,
+		anyQueueChannel:    defaultSchema.AnyQueueChannel,
+		jsonPayloads:       defaultSchema.NotifyHasJobID,
 	}
 }
 
-// Start begins listening for notifications. It blocks internally and sends
-// parsed notifications to the channel returned by Notifications().
-// Call Stop() to shut it down.
+// Start opens the connection and begins listening for notifications.
+// Parsed notifications are sent to the channel returned by Notifications().
+// If the connection cannot be set up, Start returns the error but keeps
+// retrying in the background as if an established connection had dropped,
+// reporting its progress on States(). Call Stop() to shut it down.
 func (l *Listener) Start(ctx context.Context) error {
-	ctx, l.cancel = context.WithCancel(ctx)
-
-	if err := l.subscribe(ctx); err != nil {
-		return err
+	conn, err := l.connect(ctx)
+	if err == nil {
+		l.conn = conn
+		if err = l.subscribe(ctx); err != nil {
+			conn.Close(context.Background())
+			l.conn = nil
+		}
 	}
 
+	ctx, l.cancel = context.WithCancel(ctx)
 	go l.loop(ctx)
-	return nil
+	return err
 }
 
-// Stop cancels the listener and closes the connection.
+// Stop cancels the listener, waits for it to finish and closes its channels.
 func (l *Listener) Stop() {
 	if l.cancel != nil {
 		l.cancel()
-	}
-	if l.conn != nil {
-		l.conn.Close(context.Background())
+		<-l.done
 	}
 	close(l.notifyCh)
+	close(l.stateCh)
 }
 
 // Notifications returns the channel that receives parsed notifications.
@@ -57,50 +117,101 @@ func (l *Listener) Notifications() <-chan Notification {
 	return l.notifyCh
 }
 
-// SwitchQueue unsubscribes from the old queue and subscribes to the new one.
-func (l *Listener) SwitchQueue(ctx context.Context, newQueue string) error {
-	if err := l.unsubscribe(ctx); err != nil {
-		return err
-	}
+// States returns the channel that receives connection state changes.
+// Only the latest state is kept if the consumer falls behind.
+func (l *Listener) States() <-chan ListenerState {
+	return l.stateCh
+}
+
+// SwitchQueue moves the subscription to a new queue. The LISTENs are
+// re-issued by the listener goroutine, which owns the connection.
+func (l *Listener) SwitchQueue(newQueue string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.queue = newQueue
-	return l.subscribe(ctx)
+	l.resubscribe = true
+	if l.cancelWait != nil {
+		l.cancelWait()
+	}
 }
 
 func (l *Listener) subscribe(ctx context.Context) error {
-	queueChannel := fmt.Sprintf("procrastinate_queue_v1#%s", l.queue)
-	_, err := l.conn.Exec(ctx, fmt.Sprintf("LISTEN %s", pgx.Identifier{queueChannel}.Sanitize()))
-	if err != nil {
-		return fmt.Errorf("LISTEN %s: %w", queueChannel, err)
+	l.mu.Lock()
+	queue := l.queue
+	l.resubscribe = false
+	l.mu.Unlock()
+
+	if _, err := l.conn.Exec(ctx, "UNLISTEN *"); err != nil {
+		return fmt.Errorf("UNLISTEN: %w", err)
 	}
 
-	_, err = l.conn.Exec(ctx, "LISTEN procrastinate_any_queue_v1")
-	if err != nil {
-		return fmt.Errorf("LISTEN procrastinate_any_queue_v1: %w", err)
+	// The any-queue channel already carries every queue's jobs
+	channels := []string{l.anyQueueChannel}
+	if queue != AllQueues {
+		channels = append(channels, l.queueChannelPrefix+queue)
+	}
+	for _, channel := range channels {
+		_, err := l.conn.Exec(ctx, fmt.Sprintf("LISTEN %s", pgx.Identifier{channel}.Sanitize()))
+		if err != nil {
+			return fmt.Errorf("LISTEN %s: %w", channel, err)
+		}
 	}
 
 	return nil
 }
 
-func (l *Listener) unsubscribe(ctx context.Context) error {
-	_, err := l.conn.Exec(ctx, "UNLISTEN *")
-	return err
-}
-
 func (l *Listener) loop(ctx context.Context) {
+	defer close(l.done)
+	defer func() {
+		if l.conn != nil {
+			l.conn.Close(context.Background())
+		}
+	}()
+
+	if l.conn == nil && !l.reconnect(ctx) {
+		return
+	}
+
 	for {
-		notification, err := l.conn.WaitForNotification(ctx)
+		l.mu.Lock()
+		resubscribe := l.resubscribe
+		waitCtx, cancelWait := context.WithCancel(ctx)
+		l.cancelWait = cancelWait
+		l.mu.Unlock()
+
+		if resubscribe {
+			if err := l.subscribe(ctx); err != nil {
+				cancelWait()
+				if ctx.Err() != nil || !l.reconnect(ctx) {
+					return
+				}
+				continue
+			}
+		}
+
+		notification, err := l.conn.WaitForNotification(waitCtx)
+		interrupted := waitCtx.Err() != nil
+		cancelWait()
+
 		if err != nil {
 			if ctx.Err() != nil {
 				return // context cancelled, clean shutdown
 			}
-			log.Printf("listener error: %v", err)
-			return
+			if interrupted && !l.conn.IsClosed() {
+				continue // woken up by SwitchQueue
+			}
+			if !l.reconnect(ctx) {
+				return
+			}
+			continue
 		}
 
-		var n Notification
-		if err := json.Unmarshal([]byte(notification.Payload), &n); err != nil {
-			log.Printf("failed to parse notification payload: %v", err)
-			continue
+		// 2.x payloads are the task name; only the event itself is useful
+		n := Notification{Type: "job_inserted"}
+		if l.jsonPayloads {
+			if err := json.Unmarshal([]byte(notification.Payload), &n); err != nil {
+				continue // not a Procrastinate payload
+			}
 		}
 
 		select {
@@ -110,3 +221,59 @@ func (l *Listener) loop(ctx context.Context) {
 		}
 	}
 }
+
+// reconnect replaces the broken connection, retrying with exponential
+// backoff until it succeeds or ctx is cancelled. Returns false on cancel.
+func (l *Listener) reconnect(ctx context.Context) bool {
+	if l.conn != nil {
+		l.conn.Close(context.Background())
+		l.conn = nil
+	}
+
+	backoff := listenerInitialBackoff
+	for attempt := 1; ; attempt++ {
+		if attempt > listenerDegradeAfter {
+			l.setState(ListenerDegraded)
+		} else {
+			l.setState(ListenerReconnecting)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return false
+		}
+
+		conn, err := l.connect(ctx)
+		if err == nil {
+			l.conn = conn
+			if err = l.subscribe(ctx); err == nil {
+				l.setState(ListenerConnected)
+				return true
+			}
+			conn.Close(context.Background())
+			l.conn = nil
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+
+		backoff = min(backoff*2, listenerMaxBackoff)
+	}
+}
+
+// setState publishes a state change, replacing any state the consumer has
+// not read yet so it always sees the latest one.
+func (l *Listener) setState(s ListenerState) {
+	for {
+		select {
+		case l.stateCh <- s:
+			return
+		default:
+			select {
+			case <-l.stateCh:
+			default:
+			}
+		}
+	}
+}
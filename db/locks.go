@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LockGroup is a set of todo jobs waiting on the same lock, together with
+// the job currently holding it, if any.
+type LockGroup struct {
+	Lock          string
+	Waiting       int64
+	OldestWaiting *time.Time // earliest scheduled_at among the waiting jobs
+
+	// The doing (or aborting) job holding the lock; HolderID is nil when the
+	// lock is free and the waiting jobs are only queued behind each other.
+	HolderID       *int64
+	HolderTask     *string
+	HolderQueue    *string
+	HolderWorkerID *int64
+	HeldSince      *time.Time // the holder's 'started' event, when recorded
+}
+
+// QueueingLockHold is a todo job holding a queueing lock. While it exists,
+// Procrastinate rejects new defers with the same queueing lock.
+type QueueingLockHold struct {
+	QueueingLock string
+	JobID        int64
+	TaskName     string
+	ScheduledAt  *time.Time
+}
+
+// lockGroupLimit caps the number of lock groups and queueing locks returned.
+const lockGroupLimit = 200
+
+// ListLockGroups groups the queue's todo jobs by lock and finds the job
+// holding each lock. Holders are looked up across all queues since locks are
+// global. Blocked groups come first, largest first.
+func ListLockGroups(ctx context.Context, c *Client, queue string) ([]LockGroup, error) {
+	schema := c.schema
+
+	holding := []string{string(StatusDoing)}
+	if schema.HasStatus(StatusAborting) {
+		holding = append(holding, string(StatusAborting))
+	}
+	startedAt := "NULL::timestamptz"
+	if schema.HasEventType("started") {
+		startedAt = `(SELECT max(e.at) FROM procrastinate_events e
+		              WHERE e.job_id = j.id AND e.type = 'started')`
+	}
+	workerID := "NULL::bigint"
+	if schema.HasWorkerID {
+		workerID = "j.worker_id"
+	}
+
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(`
+		SELECT t.lock, t.waiting, t.oldest,
+		       h.id, h.task_name, h.queue_name, h.worker_id, h.started_at
+		FROM (
+		  SELECT lock, count(*) AS waiting, min(scheduled_at) AS oldest
+		  FROM procrastinate_jobs
+		  WHERE status = 'todo'
+		    AND lock IS NOT NULL
+		    AND %s
+		  GROUP BY lock
+		) t
+		LEFT JOIN LATERAL (
+		  SELECT j.id, j.task_name, j.queue_name, %s AS worker_id, %s AS started_at
+		  FROM procrastinate_jobs j
+		  WHERE j.lock = t.lock
+		    AND j.status::text = ANY($2)
+		  ORDER BY j.id
+		  LIMIT 1
+		) h ON true
+		ORDER BY h.id IS NULL, t.waiting DESC, t.lock
+		LIMIT $3`, queueMatch("queue_name", queue, 1), workerID, startedAt), queue, holding, lockGroupLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []LockGroup
+	for rows.Next() {
+		var g LockGroup
+		if err := rows.Scan(&g.Lock, &g.Waiting, &g.OldestWaiting,
+			&g.HolderID, &g.HolderTask, &g.HolderQueue, &g.HolderWorkerID, &g.HeldSince); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
+// ListQueueingLocks returns the queue's todo jobs that hold a queueing lock,
+// oldest first.
+func ListQueueingLocks(ctx context.Context, c *Client, queue string) ([]QueueingLockHold, error) {
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(`
+		SELECT queueing_lock, id, task_name, scheduled_at
+		FROM procrastinate_jobs
+		WHERE status = 'todo'
+		  AND queueing_lock IS NOT NULL
+		  AND %s
+		ORDER BY scheduled_at NULLS FIRST, id
+		LIMIT $2`, queueMatch("queue_name", queue, 1)), queue, lockGroupLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holds []QueueingLockHold
+	for rows.Next() {
+		var h QueueingLockHold
+		if err := rows.Scan(&h.QueueingLock, &h.JobID, &h.TaskName, &h.ScheduledAt); err != nil {
+			return nil, err
+		}
+		holds = append(holds, h)
+	}
+	return holds, rows.Err()
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoEvents is returned by statistics that need procrastinate_events.
+var ErrNoEvents = errors.New("this Procrastinate schema has no procrastinate_events table")
+
+// Percentiles holds p50/p95/p99 of a duration sample; nil when there were
+// no samples.
+type Percentiles struct {
+	P50, P95, P99 time.Duration
+}
+
+// TaskMetrics are throughput and latency statistics for one task over a
+// window, computed from procrastinate_events.
+type TaskMetrics struct {
+	TaskName  string // empty for the all-tasks total
+	Finished  int64  // succeeded, failed and aborted attempts
+	Failed    int64
+	PerMinute float64      // finished attempts per minute
+	Wait      *Percentiles // deferred (or retried) → started
+	Run       *Percentiles // started → succeeded/failed/aborted
+}
+
+// TaskStats computes per-task metrics for the queue's attempts that
+// finished (or started, for wait times) within the window. The first
+// element is the total across all tasks; tasks follow, busiest first.
+func TaskStats(ctx context.Context, c *Client, queue string, window time.Duration) ([]TaskMetrics, error) {
+	if !c.schema.HasEvents {
+		return nil, ErrNoEvents
+	}
+
+	fromID, err := c.eventFloor(ctx, window)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recent jobs are found from the window's events, bounded by id; their
+	// full timelines then come through the job_id index. For each event,
+	// the latest queueing and start events of the same job up to and
+	// including it give the wait and run time of that attempt.
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(`
+		WITH recent AS (
+		  SELECT DISTINCT e.job_id
+		  FROM procrastinate_events e
+		  WHERE e.id >= $3
+		    AND e.at > NOW() - $2::interval
+		),
+		timeline AS (
+		  SELECT j.task_name, e.type::text AS type, e.at,
+		         max(e.at) FILTER (WHERE e.type::text IN ('deferred', 'deferred_for_retry', 'retried'))
+		           OVER attempt AS queued_at,
+		         max(e.at) FILTER (WHERE e.type::text = 'started') OVER attempt AS started_at
+		  FROM procrastinate_events e
+		  JOIN recent r ON r.job_id = e.job_id
+		  JOIN procrastinate_jobs j ON j.id = e.job_id
+		  WHERE %s
+		  WINDOW attempt AS (PARTITION BY e.job_id ORDER BY e.at, e.id ROWS UNBOUNDED PRECEDING)
+		)
+		SELECT task_name,
+		       COUNT(*) FILTER (WHERE type IN ('succeeded', 'failed', 'aborted')),
+		       COUNT(*) FILTER (WHERE type = 'failed'),
+		       percentile_cont(ARRAY[0.5, 0.95, 0.99]) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM at - queued_at))
+		         FILTER (WHERE type = 'started' AND queued_at IS NOT NULL),
+		       percentile_cont(ARRAY[0.5, 0.95, 0.99]) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM at - started_at))
+		         FILTER (WHERE type IN ('succeeded', 'failed', 'aborted') AND started_at IS NOT NULL)
+		FROM timeline
+		WHERE at > NOW() - $2::interval
+		GROUP BY GROUPING SETS ((), (task_name))
+		ORDER BY task_name IS NOT NULL, 2 DESC, task_name`, queueMatch("j.queue_name", queue, 1)),
+		queue, window.String(), fromID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	minutes := window.Minutes()
+	var stats []TaskMetrics
+	for rows.Next() {
+		var (
+			m         TaskMetrics
+			task      *string
+			wait, run []float64
+		)
+		if err := rows.Scan(&task, &m.Finished, &m.Failed, &wait, &run); err != nil {
+			return nil, err
+		}
+		if task != nil {
+			m.TaskName = *task
+		}
+		if minutes > 0 {
+			m.PerMinute = float64(m.Finished) / minutes
+		}
+		m.Wait = percentilesFromSeconds(wait)
+		m.Run = percentilesFromSeconds(run)
+		stats = append(stats, m)
+	}
+	return stats, rows.Err()
+}
+
+func percentilesFromSeconds(s []float64) *Percentiles {
+	if len(s) != 3 {
+		return nil
+	}
+	d := func(sec float64) time.Duration { return time.Duration(sec * float64(time.Second)) }
+	return &Percentiles{P50: d(s[0]), P95: d(s[1]), P99: d(s[2])}
+}
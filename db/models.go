@@ -5,6 +5,10 @@ import (
 	"time"
 )
 
+// AllQueues is the pseudo-queue that matches jobs in every queue. Functions
+// taking a queue name accept it wherever a single queue is expected.
+const AllQueues = ""
+
 // JobStatus represents the procrastinate_job_status enum.
 type JobStatus string
 
@@ -26,6 +30,25 @@ func AllStatuses() []JobStatus {
 	}
 }
 
+// Retryable reports whether a job in this status can be moved back to todo.
+func (s JobStatus) Retryable() bool {
+	switch s {
+	case StatusFailed, StatusCancelled, StatusAborted:
+		return true
+	}
+	return false
+}
+
+// Cancellable reports whether a job in this status can be cancelled.
+func (s JobStatus) Cancellable() bool {
+	return s == StatusTodo
+}
+
+// Abortable reports whether a job in this status can be asked to abort.
+func (s JobStatus) Abortable() bool {
+	return s == StatusDoing
+}
+
 // Job represents a row from procrastinate_jobs.
 type Job struct {
 	ID             int64
@@ -56,6 +79,20 @@ type StatusCount struct {
 	Count  int64
 }
 
+// TaskStatusCount holds the number of jobs of one task in one status.
+type TaskStatusCount struct {
+	TaskName string
+	Status   JobStatus
+	Count    int64
+}
+
+// QueueStatusCount holds the number of jobs in one queue in one status.
+type QueueStatusCount struct {
+	QueueName string
+	Status    JobStatus
+	Count     int64
+}
+
 // Notification represents a parsed LISTEN/NOTIFY payload.
 type Notification struct {
 	Type  string `json:"type"`
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoPeriodicTable is returned when the schema has no procrastinate_periodic_defers.
+var ErrNoPeriodicTable = errors.New("this Procrastinate schema has no procrastinate_periodic_defers table")
+
+// PeriodicDefer is the latest defer of one periodic task, from
+// procrastinate_periodic_defers. Procrastinate only keeps the latest row per
+// task and periodic id, so the schedule interval is inferred from the
+// "timestamp" argument of the previous periodic job deferred for the same id.
+type PeriodicDefer struct {
+	TaskName   string
+	PeriodicID string
+	LastDefer  time.Time     // schedule time of the latest defer
+	Interval   time.Duration // 0 when no earlier run could be found
+	JobID      *int64        // nil when the job has been deleted
+	JobStatus  *JobStatus
+	QueueName  *string
+}
+
+// NextRun returns when the next defer is due, or false if the interval is
+// unknown.
+func (p PeriodicDefer) NextRun() (time.Time, bool) {
+	if p.Interval <= 0 {
+		return time.Time{}, false
+	}
+	return p.LastDefer.Add(p.Interval), true
+}
+
+// Behind returns how long the next defer is overdue at now; zero when it is
+// not due yet or the interval is unknown.
+func (p PeriodicDefer) Behind(now time.Time) time.Duration {
+	next, ok := p.NextRun()
+	if !ok || now.Before(next) {
+		return 0
+	}
+	return now.Sub(next)
+}
+
+// MissedRuns returns how many whole intervals have passed without a defer.
+func (p PeriodicDefer) MissedRuns(now time.Time) int {
+	if p.Interval <= 0 {
+		return 0
+	}
+	return int(p.Behind(now) / p.Interval)
+}
+
+// ListPeriodicDefers returns the latest defer of every periodic task,
+// ordered by task name and periodic id.
+func ListPeriodicDefers(ctx context.Context, c *Client) ([]PeriodicDefer, error) {
+	schema := c.schema
+	if !schema.HasPeriodicDefers {
+		return nil, ErrNoPeriodicTable
+	}
+	periodicID := "''"
+	soleID := "true"
+	if schema.HasPeriodicID {
+		periodicID = "d.periodic_id"
+		soleID = `NOT EXISTS (SELECT 1 FROM procrastinate_periodic_defers o
+		                      WHERE o.task_name = d.task_name AND o.periodic_id <> d.periodic_id)`
+	}
+
+	// The previous run is the "timestamp" argument of the newest earlier job
+	// deferred for the same task and periodic id, found by walking the
+	// primary key down from the latest job. Jobs do not record the id, but
+	// each id of a task has its own arguments besides "timestamp", so match
+	// those against the latest job; once that job is deleted, only a task
+	// with a single periodic id can be matched. Non-numeric values are
+	// skipped rather than cast.
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(`
+		SELECT d.task_name, %[1]s, d.defer_timestamp,
+		       (SELECT (p.args->>'timestamp')::numeric
+		        FROM procrastinate_jobs p
+		        WHERE p.id < COALESCE(d.job_id, 9223372036854775807)
+		          AND p.task_name = d.task_name
+		          AND jsonb_typeof(p.args->'timestamp') = 'number'
+		          AND CASE WHEN j.id IS NOT NULL
+		                   THEN p.args - 'timestamp' = j.args - 'timestamp'
+		                   ELSE %[2]s END
+		        ORDER BY p.id DESC
+		        LIMIT 1) AS previous_timestamp,
+		       j.id, j.status::text, j.queue_name
+		FROM procrastinate_periodic_defers d
+		LEFT JOIN procrastinate_jobs j ON j.id = d.job_id
+		WHERE d.defer_timestamp IS NOT NULL
+		ORDER BY d.task_name, %[1]s`, periodicID, soleID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var defers []PeriodicDefer
+	for rows.Next() {
+		var (
+			p        PeriodicDefer
+			deferTS  int64
+			previous *float64
+			status   *string
+		)
+		if err := rows.Scan(&p.TaskName, &p.PeriodicID, &deferTS, &previous,
+			&p.JobID, &status, &p.QueueName); err != nil {
+			return nil, err
+		}
+		p.LastDefer = time.Unix(deferTS, 0)
+		if previous != nil && int64(*previous) < deferTS {
+			p.Interval = time.Duration(deferTS-int64(*previous)) * time.Second
+		}
+		if status != nil {
+			s := JobStatus(*status)
+			p.JobStatus = &s
+		}
+		defers = append(defers, p)
+	}
+	return defers, rows.Err()
+}
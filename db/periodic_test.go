@@ -0,0 +1,69 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriodicSchedule(t *testing.T) {
+	last := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		interval   time.Duration
+		now        time.Time
+		wantNext   time.Time
+		wantNextOK bool
+		wantBehind time.Duration
+		wantMissed int
+	}{
+		{
+			name:       "unknown interval",
+			now:        last.Add(24 * time.Hour),
+			wantNextOK: false,
+		},
+		{
+			name:       "not due yet",
+			interval:   time.Hour,
+			now:        last.Add(30 * time.Minute),
+			wantNext:   last.Add(time.Hour),
+			wantNextOK: true,
+		},
+		{
+			name:       "due exactly now",
+			interval:   time.Hour,
+			now:        last.Add(time.Hour),
+			wantNext:   last.Add(time.Hour),
+			wantNextOK: true,
+		},
+		{
+			name:       "late within one interval",
+			interval:   time.Hour,
+			now:        last.Add(90 * time.Minute),
+			wantNext:   last.Add(time.Hour),
+			wantNextOK: true,
+			wantBehind: 30 * time.Minute,
+		},
+		{
+			name:       "several runs missed",
+			interval:   10 * time.Minute,
+			now:        last.Add(45 * time.Minute),
+			wantNext:   last.Add(10 * time.Minute),
+			wantNextOK: true,
+			wantBehind: 35 * time.Minute,
+			wantMissed: 3,
+		},
+	}
+	for _, tt := range tests {
+		p := PeriodicDefer{TaskName: "t", LastDefer: last, Interval: tt.interval}
+		next, ok := p.NextRun()
+		if ok != tt.wantNextOK || !next.Equal(tt.wantNext) {
+			t.Errorf("%s: NextRun() = %v, %v; want %v, %v", tt.name, next, ok, tt.wantNext, tt.wantNextOK)
+		}
+		if got := p.Behind(tt.now); got != tt.wantBehind {
+			t.Errorf("%s: Behind() = %v, want %v", tt.name, got, tt.wantBehind)
+		}
+		if got := p.MissedRuns(tt.now); got != tt.wantMissed {
+			t.Errorf("%s: MissedRuns() = %d, want %d", tt.name, got, tt.wantMissed)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultPurgeBatch is the number of jobs PurgeJobs deletes per statement.
+const DefaultPurgeBatch = 1000
+
+// PurgeFilter selects finished jobs to delete, like Procrastinate's
+// remove_old_jobs: succeeded, cancelled and aborted jobs (and failed ones
+// with IncludeFailed) whose latest event is older than OlderThan.
+type PurgeFilter struct {
+	Queue         string // AllQueues purges every queue
+	OlderThan     time.Duration
+	IncludeFailed bool
+}
+
+// PurgeCount is the number of jobs a purge would delete in one queue and
+// status.
+type PurgeCount struct {
+	Queue  string    `json:"queue"`
+	Status JobStatus `json:"status"`
+	Jobs   int64     `json:"jobs"`
+}
+
+// ParseAge parses a positive age such as 90m, 72h or 30d.
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("age is required")
+	}
+	d, err := parseRelative("+" + s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid age %q: use a duration like 72h or 30d", s)
+	}
+	return d, nil
+}
+
+// statuses returns the statuses the filter purges that this schema has.
+func (f PurgeFilter) statuses(s *Schema) []string {
+	want := []JobStatus{StatusSucceeded, StatusCancelled, StatusAborted}
+	if f.IncludeFailed {
+		want = append(want, StatusFailed)
+	}
+	var out []string
+	for _, st := range want {
+		if slices.Contains(s.Statuses, st) {
+			out = append(out, string(st))
+		}
+	}
+	return out
+}
+
+// conditions returns the WHERE conditions matching jobs to purge whose
+// latest event is before the cutoff expression.
+func (f PurgeFilter) conditions(c *Client, args *sqlArgs, cutoff string) []string {
+	conds := []string{"j.status::text = ANY(" + args.add(f.statuses(c.schema)) + ")"}
+	if f.Queue != AllQueues {
+		conds = append(conds, "j.queue_name = "+args.add(f.Queue))
+	}
+	return append(conds,
+		"EXISTS (SELECT 1 FROM procrastinate_events e WHERE e.job_id = j.id)",
+		"NOT EXISTS (SELECT 1 FROM procrastinate_events e WHERE e.job_id = j.id AND e.at >= "+cutoff+")",
+	)
+}
+
+func (f PurgeFilter) validate(c *Client) error {
+	if f.OlderThan <= 0 {
+		return fmt.Errorf("age must be positive")
+	}
+	if !c.schema.HasEvents {
+		return fmt.Errorf("purging needs the procrastinate_events table to know when jobs finished")
+	}
+	return nil
+}
+
+// PurgePreview counts the jobs a purge would delete, by queue and status,
+// and returns the cutoff it counted against so PurgeJobs can delete exactly
+// those jobs. It deletes nothing.
+func PurgePreview(ctx context.Context, c *Client, f PurgeFilter) ([]PurgeCount, time.Time, error) {
+	if err := f.validate(c); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var cutoff time.Time
+	if err := c.pool.QueryRow(ctx, `SELECT NOW() - $1::float8 * INTERVAL '1 second'`,
+		f.OlderThan.Seconds()).Scan(&cutoff); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var args sqlArgs
+	before := args.add(cutoff) + "::timestamptz"
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(`
+		SELECT j.queue_name, j.status::text, COUNT(*)
+		FROM procrastinate_jobs j
+		%s
+		GROUP BY j.queue_name, j.status
+		ORDER BY j.queue_name, j.status`, whereClause(f.conditions(c, &args, before))), args...)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer rows.Close()
+
+	var counts []PurgeCount
+	for rows.Next() {
+		var c PurgeCount
+		if err := rows.Scan(&c.Queue, &c.Status, &c.Jobs); err != nil {
+			return nil, time.Time{}, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, cutoff, rows.Err()
+}
+
+// PurgeJobs deletes the jobs matched by the filter whose latest event is
+// before cutoff, as returned by PurgePreview, batch jobs per statement so
+// no single transaction holds row locks for long. Jobs finishing after the
+// preview are left alone. Their events are deleted with them, explicitly
+// when the schema's foreign key does not cascade. Returns the number of
+// jobs deleted, including when a later batch fails.
+func PurgeJobs(ctx context.Context, c *Client, f PurgeFilter, cutoff time.Time, batch int) (int64, error) {
+	if err := f.validate(c); err != nil {
+		return 0, err
+	}
+	if batch <= 0 {
+		batch = DefaultPurgeBatch
+	}
+
+	var args sqlArgs
+	before := args.add(cutoff) + "::timestamptz"
+	conds := f.conditions(c, &args, before)
+	limit := args.add(batch)
+	query := fmt.Sprintf(`
+		DELETE FROM procrastinate_jobs
+		WHERE id IN (
+		  SELECT j.id
+		  FROM procrastinate_jobs j
+		  %s
+		  LIMIT %s
+		)`, whereClause(conds), limit)
+	if !c.schema.EventsCascade {
+		// The foreign key is only checked at the end of the statement, so
+		// both deletes can share one snapshot of the batch.
+		query = fmt.Sprintf(`
+			WITH batch AS (
+			  SELECT j.id
+			  FROM procrastinate_jobs j
+			  %s
+			  LIMIT %s
+			), events AS (
+			  DELETE FROM procrastinate_events WHERE job_id IN (SELECT id FROM batch)
+			)
+			DELETE FROM procrastinate_jobs WHERE id IN (SELECT id FROM batch)`,
+			whereClause(conds), limit)
+	}
+	var deleted int64
+	for {
+		tag, err := c.pool.Exec(ctx, query, args...)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += tag.RowsAffected()
+		if tag.RowsAffected() < int64(batch) {
+			return deleted, nil
+		}
+	}
+}
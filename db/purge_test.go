@@ -0,0 +1,38 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "90m", want: 90 * time.Minute},
+		{in: "72h", want: 72 * time.Hour},
+		{in: "30d", want: 30 * 24 * time.Hour},
+		{in: " 1.5d ", want: 36 * time.Hour},
+		{in: "", wantErr: true},
+		{in: "0", wantErr: true},
+		{in: "0d", wantErr: true},
+		{in: "-1d", wantErr: true},
+		{in: "-5m", wantErr: true},
+		{in: "30", wantErr: true},
+		{in: "thirty days", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAge(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseAge(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseAge(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
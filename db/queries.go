@@ -2,14 +2,13 @@ package db
 
 import (
 	"context"
+	"fmt"
 	"time"
-
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // ListQueues returns all distinct queue names.
-func ListQueues(ctx context.Context, pool *pgxpool.Pool) ([]string, error) {
-	rows, err := pool.Query(ctx,
+func ListQueues(ctx context.Context, c *Client) ([]string, error) {
+	rows, err := c.pool.Query(ctx,
 		`SELECT DISTINCT queue_name FROM procrastinate_jobs ORDER BY queue_name`)
 	if err != nil {
 		return nil, err
@@ -28,14 +27,13 @@ func ListQueues(ctx context.Context, pool *pgxpool.Pool) ([]string, error) {
 }
 
 // ListJobs returns jobs for a queue, ordered by id DESC, with limit/offset.
-func ListJobs(ctx context.Context, pool *pgxpool.Pool, queue string, limit, offset int) ([]Job, error) {
-	rows, err := pool.Query(ctx, `
-		SELECT id, queue_name, task_name, priority, lock, queueing_lock,
-		       args, status, scheduled_at, attempts, abort_requested, worker_id
+func ListJobs(ctx context.Context, c *Client, queue string, limit, offset int) ([]Job, error) {
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(`
+		SELECT %s
 		FROM procrastinate_jobs
 		WHERE queue_name = $1
 		ORDER BY id DESC
-		LIMIT $2 OFFSET $3`,
+		LIMIT $2 OFFSET $3`, c.schema.jobColumns("")),
 		queue, limit, offset)
 	if err != nil {
 		return nil, err
@@ -45,38 +43,22 @@ func ListJobs(ctx context.Context, pool *pgxpool.Pool, queue string, limit, offs
 	return scanJobs(rows)
 }
 
-// ListJobsFiltered returns jobs for a queue with an optional status filter.
-// When status is empty, returns all jobs with doing first, todo second, then everything else by time (newest first).
+// ListJobsFiltered returns jobs matching the filter.
+// When no status is set, returns all jobs with doing first, todo second, then everything else by time (newest first).
 // When status is set, returns only jobs with that status sorted by id DESC.
-func ListJobsFiltered(ctx context.Context, pool *pgxpool.Pool, queue, status string, limit, offset int) ([]Job, error) {
-	var query string
-	var args []any
-
-	if status == "" {
-		query = `
-		SELECT id, queue_name, task_name, priority, lock, queueing_lock,
-		       args, status, scheduled_at, attempts, abort_requested, worker_id
-		FROM procrastinate_jobs
-		WHERE queue_name = $1
-		ORDER BY CASE status
-			WHEN 'doing' THEN 0
-			WHEN 'todo' THEN 1
-			ELSE 2
-		END, id DESC
-		LIMIT $2 OFFSET $3`
-		args = []any{queue, limit, offset}
-	} else {
-		query = `
-		SELECT id, queue_name, task_name, priority, lock, queueing_lock,
-		       args, status, scheduled_at, attempts, abort_requested, worker_id
+func ListJobsFiltered(ctx context.Context, c *Client, filter JobFilter, limit, offset int) ([]Job, error) {
+	var args sqlArgs
+	conds := filter.conditions(&args)
+
+	query := fmt.Sprintf(`
+		SELECT %s
 		FROM procrastinate_jobs
-		WHERE queue_name = $1 AND status = $2
-		ORDER BY id DESC
-		LIMIT $3 OFFSET $4`
-		args = []any{queue, status, limit, offset}
-	}
+		%s
+		ORDER BY %s
+		LIMIT %s OFFSET %s`, c.schema.jobColumns(""), whereClause(conds), filter.orderBy(),
+		args.add(limit), args.add(offset))
 
-	rows, err := pool.Query(ctx, query, args...)
+	rows, err := c.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -86,12 +68,11 @@ func ListJobsFiltered(ctx context.Context, pool *pgxpool.Pool, queue, status str
 }
 
 // GetJob returns a single job by ID.
-func GetJob(ctx context.Context, pool *pgxpool.Pool, id int64) (*Job, error) {
-	row := pool.QueryRow(ctx, `
-		SELECT id, queue_name, task_name, priority, lock, queueing_lock,
-		       args, status, scheduled_at, attempts, abort_requested, worker_id
+func GetJob(ctx context.Context, c *Client, id int64) (*Job, error) {
+	row := c.pool.QueryRow(ctx, fmt.Sprintf(`
+		SELECT %s
 		FROM procrastinate_jobs
-		WHERE id = $1`, id)
+		WHERE id = $1`, c.schema.jobColumns("")), id)
 
 	var j Job
 	err := row.Scan(
@@ -104,13 +85,29 @@ func GetJob(ctx context.Context, pool *pgxpool.Pool, id int64) (*Job, error) {
 	return &j, nil
 }
 
+// GetJobs returns the jobs with the given IDs, ordered by id DESC.
+// Missing IDs are silently skipped.
+func GetJobs(ctx context.Context, c *Client, ids []int64) ([]Job, error) {
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM procrastinate_jobs
+		WHERE id = ANY($1)
+		ORDER BY id DESC`, c.schema.jobColumns("")), ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanJobs(rows)
+}
+
 // GetJobEvents returns events for a job, ordered by timestamp.
-func GetJobEvents(ctx context.Context, pool *pgxpool.Pool, jobID int64) ([]JobEvent, error) {
-	rows, err := pool.Query(ctx, `
+func GetJobEvents(ctx context.Context, c *Client, jobID int64) ([]JobEvent, error) {
+	rows, err := c.pool.Query(ctx, `
 		SELECT id, job_id, type, at
 		FROM procrastinate_events
 		WHERE job_id = $1
-		ORDER BY at ASC`, jobID)
+		ORDER BY at ASC, id ASC`, jobID)
 	if err != nil {
 		return nil, err
 	}
@@ -128,13 +125,13 @@ func GetJobEvents(ctx context.Context, pool *pgxpool.Pool, jobID int64) ([]JobEv
 }
 
 // CountJobsByStatus returns job counts grouped by status for a queue.
-func CountJobsByStatus(ctx context.Context, pool *pgxpool.Pool, queue string) ([]StatusCount, error) {
-	rows, err := pool.Query(ctx, `
+func CountJobsByStatus(ctx context.Context, c *Client, queue string) ([]StatusCount, error) {
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(`
 		SELECT status, COUNT(*)
 		FROM procrastinate_jobs
-		WHERE queue_name = $1
+		WHERE %s
 		GROUP BY status
-		ORDER BY status`, queue)
+		ORDER BY status`, queueMatch("queue_name", queue, 1)), queue)
 	if err != nil {
 		return nil, err
 	}
@@ -151,18 +148,66 @@ func CountJobsByStatus(ctx context.Context, pool *pgxpool.Pool, queue string) ([
 	return counts, rows.Err()
 }
 
+// CountJobsByTaskAndStatus returns job counts grouped by task and status
+// for a queue.
+func CountJobsByTaskAndStatus(ctx context.Context, c *Client, queue string) ([]TaskStatusCount, error) {
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(`
+		SELECT task_name, status, COUNT(*)
+		FROM procrastinate_jobs
+		WHERE %s
+		GROUP BY task_name, status
+		ORDER BY task_name, status`, queueMatch("queue_name", queue, 1)), queue)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []TaskStatusCount
+	for rows.Next() {
+		var tc TaskStatusCount
+		if err := rows.Scan(&tc.TaskName, &tc.Status, &tc.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, tc)
+	}
+	return counts, rows.Err()
+}
+
+// CountJobsByQueueAndStatus returns job counts grouped by queue and status
+// across every queue.
+func CountJobsByQueueAndStatus(ctx context.Context, c *Client) ([]QueueStatusCount, error) {
+	rows, err := c.pool.Query(ctx, `
+		SELECT queue_name, status, COUNT(*)
+		FROM procrastinate_jobs
+		GROUP BY queue_name, status
+		ORDER BY queue_name, status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []QueueStatusCount
+	for rows.Next() {
+		var qc QueueStatusCount
+		if err := rows.Scan(&qc.QueueName, &qc.Status, &qc.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, qc)
+	}
+	return counts, rows.Err()
+}
+
 // ListRecentJobs returns jobs that were created after the given timestamp.
-func ListRecentJobs(ctx context.Context, pool *pgxpool.Pool, queue string, since time.Time) ([]Job, error) {
-	rows, err := pool.Query(ctx, `
-		SELECT j.id, j.queue_name, j.task_name, j.priority, j.lock, j.queueing_lock,
-		       j.args, j.status, j.scheduled_at, j.attempts, j.abort_requested, j.worker_id
+func ListRecentJobs(ctx context.Context, c *Client, queue string, since time.Time) ([]Job, error) {
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(`
+		SELECT %s
 		FROM procrastinate_jobs j
 		JOIN procrastinate_events e ON e.job_id = j.id
-		WHERE j.queue_name = $1
+		WHERE %s
 		  AND e.type = 'deferred'
 		  AND e.at >= $2
 		ORDER BY j.id DESC
-		LIMIT 200`, queue, since)
+		LIMIT 200`, c.schema.jobColumns("j"), queueMatch("j.queue_name", queue, 1)), queue, since)
 	if err != nil {
 		return nil, err
 	}
@@ -175,32 +220,11 @@ func ListRecentJobs(ctx context.Context, pool *pgxpool.Pool, queue string, since
 // It combines two strategies:
 // 1. Jobs in 'doing' with a dead/missing worker (stale heartbeat)
 // 2. Jobs in 'todo' sitting too long without progress (excluding future-scheduled)
-func ListOrphanedJobs(ctx context.Context, pool *pgxpool.Pool, queue string, threshold time.Duration) ([]Job, error) {
-	rows, err := pool.Query(ctx, `
-		-- Doing jobs with dead or missing worker
-		SELECT j.id, j.queue_name, j.task_name, j.priority, j.lock, j.queueing_lock,
-		       j.args, j.status, j.scheduled_at, j.attempts, j.abort_requested, j.worker_id
-		FROM procrastinate_jobs j
-		LEFT JOIN procrastinate_workers w ON j.worker_id = w.id
-		WHERE j.queue_name = $1
-		  AND j.status = 'doing'
-		  AND (w.id IS NULL OR w.last_heartbeat < NOW() - $2::interval)
-
-		UNION ALL
-
-		-- Todo jobs sitting too long
-		SELECT j.id, j.queue_name, j.task_name, j.priority, j.lock, j.queueing_lock,
-		       j.args, j.status, j.scheduled_at, j.attempts, j.abort_requested, j.worker_id
-		FROM procrastinate_jobs j
-		WHERE j.queue_name = $1
-		  AND j.status = 'todo'
-		  AND (j.scheduled_at IS NULL OR j.scheduled_at <= NOW())
-		  AND NOT EXISTS (
-		    SELECT 1 FROM procrastinate_events e
-		    WHERE e.job_id = j.id AND e.at > NOW() - $2::interval
-		  )
-		ORDER BY id ASC`,
-		queue, threshold.String())
+// Schemas without a workers table treat 'doing' jobs with no recent event as dead.
+func ListOrphanedJobs(ctx context.Context, c *Client, queue string, threshold time.Duration) ([]Job, error) {
+	schema := c.schema
+	rows, err := c.pool.Query(ctx, orphanedJobsQuery(schema, schema.jobColumns("j"), queue)+`
+		ORDER BY id ASC`, queue, threshold.String())
 	if err != nil {
 		return nil, err
 	}
@@ -209,6 +233,55 @@ func ListOrphanedJobs(ctx context.Context, pool *pgxpool.Pool, queue string, thr
 	return scanJobs(rows)
 }
 
+// CountOrphanedJobs returns the number of jobs ListOrphanedJobs would return.
+func CountOrphanedJobs(ctx context.Context, c *Client, queue string, threshold time.Duration) (int64, error) {
+	var n int64
+	err := c.pool.QueryRow(ctx,
+		"SELECT COUNT(*) FROM ("+orphanedJobsQuery(c.schema, "j.id", queue)+") o",
+		queue, threshold.String()).Scan(&n)
+	return n, err
+}
+
+// orphanedJobsQuery builds the orphaned jobs query selecting columns, with
+// the queue as $1 and the threshold interval as $2.
+func orphanedJobsQuery(schema *Schema, columns, queue string) string {
+	return fmt.Sprintf(`
+		SELECT %s
+		FROM procrastinate_jobs j
+		WHERE %s
+		  AND %s`, columns, queueMatch("j.queue_name", queue, 1), orphanedCondition(schema, "$2"))
+}
+
+// orphanedCondition matches an orphaned job row "j", with threshold the
+// placeholder of the threshold interval:
+//  1. a doing job whose worker is missing or has a stale heartbeat (without
+//     a workers table, a doing job with no recent event)
+//  2. a todo job due to run but with no recent event
+func orphanedCondition(schema *Schema, threshold string) string {
+	deadDoing := `NOT EXISTS (
+		    SELECT 1 FROM procrastinate_workers w
+		    WHERE w.id = j.worker_id AND w.last_heartbeat >= NOW() - ` + threshold + `::interval
+		  )`
+	if !schema.HasWorkers {
+		deadDoing = `NOT EXISTS (
+		    SELECT 1 FROM procrastinate_events e
+		    WHERE e.job_id = j.id AND e.at > NOW() - ` + threshold + `::interval
+		  )`
+	}
+
+	return `(
+		  -- Doing jobs with dead or missing worker
+		  (j.status = 'doing' AND ` + deadDoing + `)
+		  -- Todo jobs sitting too long
+		  OR (j.status = 'todo'
+		    AND (j.scheduled_at IS NULL OR j.scheduled_at <= NOW())
+		    AND NOT EXISTS (
+		      SELECT 1 FROM procrastinate_events e
+		      WHERE e.job_id = j.id AND e.at > NOW() - ` + threshold + `::interval
+		    ))
+		)`
+}
+
 // scanJobs is a helper that scans job rows into a slice.
 func scanJobs(rows interface {
 	Next() bool
@@ -0,0 +1,440 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Query is a parsed job filter expression: whitespace-separated terms that
+// must all match, each a field, an operator and a value:
+//
+//	status:failed task:send_* attempts>2 args.order_id=1003 scheduled<-1h
+//
+// Fields are status, task, queue, lock, queueing_lock, id, attempts,
+// priority, scheduled and args.<path>. Operators are : and = (equal, or a
+// glob match when the value has * or ?), !=, <, <=, > and >=. Values with
+// spaces can be double-quoted. status accepts a comma-separated list.
+// scheduled compares against a time relative to now (-1h, +30m, -7d) or a
+// date (2006-01-02, 2006-01-02T15:04 or RFC 3339).
+//
+// args@>{"order_id":1003} matches jobs whose args contain the JSON
+// document, and args:word matches args whose JSON text contains the word,
+// ignoring case. A bare word matches the task name or the args text, so
+// typing part of a task name still finds its jobs. A fully quoted term such
+// as "order:1003" is always searched as text.
+type Query struct {
+	source string
+	terms  []queryTerm
+}
+
+type queryTerm struct {
+	column string   // SQL column, empty for args
+	path   []string // args path
+	op     string
+	value  any
+	glob   bool // value is a LIKE pattern
+	orTask bool // an ILIKE that also matches task_name
+
+	highlight []string // text to highlight in matching args
+}
+
+// Text and glob-matched fields, by name.
+var textFields = map[string]string{
+	"task":          "task_name",
+	"queue":         "queue_name",
+	"lock":          "lock",
+	"queueing_lock": "queueing_lock",
+}
+
+// Integer fields, by name.
+var numberFields = map[string]string{
+	"id":       "id",
+	"attempts": "attempts",
+	"priority": "priority",
+}
+
+// queryOps lists the operators, longest first so "!=" is not read as "!".
+var queryOps = []string{"@>", "!=", ">=", "<=", ":", "=", ">", "<"}
+
+// ParseQuery parses a filter expression. An empty expression returns nil.
+func ParseQuery(s string) (*Query, error) {
+	tokens, err := splitQuery(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	q := &Query{source: strings.TrimSpace(s)}
+	for _, tok := range tokens {
+		if tok.quoted {
+			// "order:1003" searches for the text, operators and all
+			q.terms = append(q.terms, freeTextTerm(tok.text))
+			continue
+		}
+		term, err := parseTerm(tok.text)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", tok.text, err)
+		}
+		q.terms = append(q.terms, term)
+	}
+	return q, nil
+}
+
+// String returns the expression the query was parsed from.
+func (q *Query) String() string {
+	if q == nil {
+		return ""
+	}
+	return q.source
+}
+
+// queryToken is one whitespace-separated term of a query.
+type queryToken struct {
+	text   string // the term with its quotes removed
+	quoted bool   // the whole term was one quoted string: a literal search
+}
+
+// splitQuery splits on whitespace outside double quotes, dropping the quotes.
+// Inside JSON objects and arrays everything is kept as written, including
+// brackets inside JSON strings.
+func splitQuery(s string) ([]queryToken, error) {
+	var (
+		tokens  []queryToken
+		cur     strings.Builder
+		inQuote bool
+		started bool
+		depth   int  // JSON nesting
+		inStr   bool // inside a JSON string
+		escaped bool // previous rune was a backslash in a JSON string
+
+		// The token opened with a quote and nothing followed its close.
+		quoteStart, quoteEnd bool
+	)
+	write := func(r rune) {
+		cur.WriteRune(r)
+		if !inQuote {
+			quoteEnd = false
+		}
+		started = true
+	}
+	for _, r := range s {
+		switch {
+		case depth > 0:
+			cur.WriteRune(r)
+			switch {
+			case escaped:
+				escaped = false
+			case inStr && r == '\\':
+				escaped = true
+			case r == '"':
+				inStr = !inStr
+			case !inStr && (r == '{' || r == '['):
+				depth++
+			case !inStr && (r == '}' || r == ']'):
+				depth--
+			}
+		case (r == '{' || r == '[') && !inQuote:
+			depth++
+			write(r)
+		case r == '"':
+			if !started {
+				quoteStart = true
+			}
+			inQuote = !inQuote
+			quoteEnd = !inQuote
+			started = true
+		case unicode.IsSpace(r) && !inQuote:
+			if started {
+				tokens = append(tokens, queryToken{text: cur.String(), quoted: quoteStart && quoteEnd})
+				cur.Reset()
+				started, quoteStart, quoteEnd = false, false, false
+			}
+		default:
+			write(r)
+		}
+	}
+	if inQuote || inStr {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if depth > 0 {
+		return nil, fmt.Errorf("unterminated JSON value")
+	}
+	if started {
+		tokens = append(tokens, queryToken{text: cur.String(), quoted: quoteStart && quoteEnd})
+	}
+	return tokens, nil
+}
+
+func parseTerm(tok string) (queryTerm, error) {
+	i := strings.IndexAny(tok, ":=!<>@")
+	if i < 0 {
+		return freeTextTerm(tok), nil
+	}
+	if i == 0 {
+		return queryTerm{}, fmt.Errorf("expected field, operator and value, e.g. status:failed")
+	}
+	field := strings.ToLower(tok[:i])
+	var op string
+	for _, o := range queryOps {
+		if strings.HasPrefix(tok[i:], o) {
+			op = o
+			break
+		}
+	}
+	if op == "" {
+		return freeTextTerm(tok), nil // a lone "@" or "!", as in an email address
+	}
+	value := tok[i+len(op):]
+	if value == "" {
+		return queryTerm{}, fmt.Errorf("missing value")
+	}
+	if field == "args" {
+		switch op {
+		case "@>":
+			return argsContainsTerm(value)
+		case ":":
+			return argsTextTerm(value), nil
+		}
+		return queryTerm{}, fmt.Errorf("args supports @> and :, or args.<key> for a single value")
+	}
+	if op == "@>" {
+		return queryTerm{}, fmt.Errorf("@> only applies to args")
+	}
+	if op == ":" {
+		op = "="
+	}
+	equality := op == "=" || op == "!="
+
+	switch {
+	case field == "status":
+		if !equality {
+			return queryTerm{}, fmt.Errorf("status only supports :, = and !=")
+		}
+		statuses := strings.Split(strings.ToLower(value), ",")
+		for _, st := range statuses {
+			if !slices.Contains(AllStatuses(), JobStatus(st)) {
+				return queryTerm{}, fmt.Errorf("unknown status %q", st)
+			}
+		}
+		return queryTerm{column: "status", op: op, value: statuses}, nil
+
+	case textFields[field] != "":
+		if !equality {
+			return queryTerm{}, fmt.Errorf("%s only supports :, = and !=", field)
+		}
+		return textTerm(textFields[field], nil, op, value), nil
+
+	case numberFields[field] != "":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return queryTerm{}, fmt.Errorf("%s needs a whole number", field)
+		}
+		return queryTerm{column: numberFields[field], op: op, value: n}, nil
+
+	case field == "scheduled":
+		if equality {
+			return queryTerm{}, fmt.Errorf("scheduled only supports <, <=, > and >=")
+		}
+		if d, err := parseRelative(value); err == nil {
+			return queryTerm{column: "scheduled_at", op: op, value: d}, nil
+		}
+		t, err := parseQueryTime(value)
+		if err != nil {
+			return queryTerm{}, fmt.Errorf("scheduled needs a relative time like -1h or a date like 2006-01-02")
+		}
+		return queryTerm{column: "scheduled_at", op: op, value: t}, nil
+
+	case strings.HasPrefix(field, "args."):
+		path := strings.Split(tok[len("args."):i], ".")
+		if slices.Contains(path, "") {
+			return queryTerm{}, fmt.Errorf("empty key in args path")
+		}
+		if equality {
+			term := textTerm("", path, op, value)
+			if op == "=" && !term.glob {
+				term.highlight = []string{value}
+			}
+			return term, nil
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return queryTerm{}, fmt.Errorf("%s comparisons need a number", op)
+		}
+		return queryTerm{path: path, op: op, value: n}, nil
+	}
+
+	return queryTerm{}, fmt.Errorf("unknown field %q", field)
+}
+
+// textTerm matches a column or args path as text, by glob when the value
+// has wildcards.
+func textTerm(column string, path []string, op, value string) queryTerm {
+	if strings.ContainsAny(value, "*?") {
+		return queryTerm{column: column, path: path, op: op, value: globToLike(value), glob: true}
+	}
+	return queryTerm{column: column, path: path, op: op, value: value}
+}
+
+// argsContainsTerm matches args containing a JSON document.
+func argsContainsTerm(value string) (queryTerm, error) {
+	var doc any
+	if err := json.Unmarshal([]byte(value), &doc); err != nil {
+		return queryTerm{}, fmt.Errorf("@> needs a JSON value: %w", err)
+	}
+	return queryTerm{column: "args", op: "@>", value: value, highlight: jsonLeaves(doc)}, nil
+}
+
+// argsTextTerm matches args whose JSON text contains value, ignoring case.
+func argsTextTerm(value string) queryTerm {
+	return queryTerm{
+		column:    "args::text",
+		op:        "ILIKE",
+		value:     "%" + globToLike(value) + "%",
+		highlight: []string{value},
+	}
+}
+
+// freeTextTerm matches jobs whose task name or args JSON text contains
+// value, ignoring case.
+func freeTextTerm(value string) queryTerm {
+	t := argsTextTerm(value)
+	t.orTask = true
+	return t
+}
+
+// jsonLeaves returns the scalar values in a decoded JSON document as text.
+func jsonLeaves(doc any) []string {
+	switch v := doc.(type) {
+	case map[string]any:
+		var leaves []string
+		for _, child := range v {
+			leaves = append(leaves, jsonLeaves(child)...)
+		}
+		return leaves
+	case []any:
+		var leaves []string
+		for _, child := range v {
+			leaves = append(leaves, jsonLeaves(child)...)
+		}
+		return leaves
+	case nil:
+		return nil
+	default:
+		b, _ := json.Marshal(v)
+		return []string{strings.Trim(string(b), `"`)}
+	}
+}
+
+// Highlights returns the text that matching jobs' args were searched for,
+// to highlight in their args.
+func (q *Query) Highlights() []string {
+	if q == nil {
+		return nil
+	}
+	var out []string
+	for _, t := range q.terms {
+		out = append(out, t.highlight...)
+	}
+	return out
+}
+
+// parseRelative parses a signed offset from now such as -1h, +30m or -7d.
+func parseRelative(s string) (time.Duration, error) {
+	if s[0] != '-' && s[0] != '+' {
+		return 0, fmt.Errorf("not relative")
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+func parseQueryTime(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// conditions compiles the query to WHERE conditions, adding their
+// parameters to args.
+func (q *Query) conditions(args *sqlArgs) []string {
+	if q == nil {
+		return nil
+	}
+	conds := make([]string, 0, len(q.terms))
+	for _, t := range q.terms {
+		conds = append(conds, t.condition(args))
+	}
+	return conds
+}
+
+func (t queryTerm) condition(args *sqlArgs) string {
+	if t.path != nil {
+		return t.argsCondition(args)
+	}
+
+	switch v := t.value.(type) {
+	case []string: // status
+		cond := "status::text = ANY(" + args.add(v) + ")"
+		if t.op == "!=" {
+			cond = "NOT " + cond
+		}
+		return cond
+	case time.Duration:
+		return fmt.Sprintf("%s %s NOW() + %s::float8 * INTERVAL '1 second'", t.column, t.op, args.add(v.Seconds()))
+	case string:
+		switch t.op {
+		case "@>":
+			return fmt.Sprintf("args @> %s::jsonb", args.add(v))
+		case "ILIKE":
+			p := args.add(v)
+			if t.orTask {
+				return fmt.Sprintf("(task_name ILIKE %s OR %s ILIKE %s)", p, t.column, p)
+			}
+			return fmt.Sprintf("%s ILIKE %s", t.column, p)
+		}
+		return textCondition(t.column, t.op, t.glob, args.add(v))
+	default:
+		return fmt.Sprintf("%s %s %s", t.column, t.op, args.add(v))
+	}
+}
+
+// argsCondition matches a value inside the args JSON. Ordering comparisons
+// only match numbers.
+func (t queryTerm) argsCondition(args *sqlArgs) string {
+	path := args.add(t.path)
+	if v, ok := t.value.(string); ok {
+		return textCondition(fmt.Sprintf("(args #>> %s::text[])", path), t.op, t.glob, args.add(v))
+	}
+	return fmt.Sprintf(`CASE WHEN jsonb_typeof(args #> %s::text[]) = 'number'
+		THEN (args #>> %s::text[])::numeric END %s %s::numeric`, path, path, t.op, args.add(t.value))
+}
+
+// textCondition compares expr with the parameter placeholder p. Negated
+// matches include rows where expr is NULL.
+func textCondition(expr, op string, glob bool, p string) string {
+	switch {
+	case glob && op == "!=":
+		return fmt.Sprintf("(%s IS NULL OR %s NOT LIKE %s)", expr, expr, p)
+	case glob:
+		return fmt.Sprintf("%s LIKE %s", expr, p)
+	case op == "!=":
+		return fmt.Sprintf("%s IS DISTINCT FROM %s", expr, p)
+	default:
+		return fmt.Sprintf("%s = %s", expr, p)
+	}
+}
@@ -0,0 +1,260 @@
+package db
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitQuery(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []queryToken
+	}{
+		{"", nil},
+		{"  status:failed   task:x ", []queryToken{{text: "status:failed"}, {text: "task:x"}}},
+		{`args.subject:"Welcome back" 1003`, []queryToken{{text: "args.subject:Welcome back"}, {text: "1003"}}},
+		{`"order:1003" "a b"`, []queryToken{{text: "order:1003", quoted: true}, {text: "a b", quoted: true}}},
+		{`"order":1003`, []queryToken{{text: "order:1003"}}},
+		{`args@>{"a": "b c"}`, []queryToken{{text: `args@>{"a": "b c"}`}}},
+		{`args@>{"a":"}"} x`, []queryToken{{text: `args@>{"a":"}"}`}, {text: "x"}}},
+		{`args@>{"a":"\"}"}`, []queryToken{{text: `args@>{"a":"\"}"}`}}},
+		{`args@>{"a":[1,{"b":2}]} y`, []queryToken{{text: `args@>{"a":[1,{"b":2}]}`}, {text: "y"}}},
+		{`args@>["x y"]`, []queryToken{{text: `args@>["x y"]`}}},
+	}
+	for _, tt := range tests {
+		got, err := splitQuery(tt.in)
+		if err != nil {
+			t.Errorf("splitQuery(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitQuery(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseQueryEmpty(t *testing.T) {
+	for _, in := range []string{"", "   "} {
+		q, err := ParseQuery(in)
+		if q != nil || err != nil {
+			t.Errorf("ParseQuery(%q) = %v, %v; want nil, nil", in, q, err)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr string
+	}{
+		{":x", "expected field, operator and value"},
+		{"task:", "missing value"},
+		{"status:nope", `unknown status "nope"`},
+		{"status>failed", "status only supports"},
+		{"task<x", "task only supports"},
+		{"id:abc", "id needs a whole number"},
+		{"attempts>2.5", "attempts needs a whole number"},
+		{"scheduled:1h", "scheduled only supports"},
+		{"scheduled<soon", "scheduled needs a relative time"},
+		{"args.=x", "empty key in args path"},
+		{"args.a..b=x", "empty key in args path"},
+		{"args.a>abc", "> comparisons need a number"},
+		{"args=x", "args supports @> and :"},
+		{"task@>{}", "@> only applies to args"},
+		{"args@>nope", "@> needs a JSON value"},
+		{"bogus:1", `unknown field "bogus"`},
+		{`"open`, "unterminated quote"},
+		{`args@>{"a":1`, "unterminated JSON value"},
+		{`args@>{"a":"}`, "unterminated quote"},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.in)
+		if err == nil {
+			t.Errorf("ParseQuery(%q) = %v, want error containing %q", tt.in, q, tt.wantErr)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ParseQuery(%q) error = %q, want it to contain %q", tt.in, err, tt.wantErr)
+		}
+	}
+}
+
+func TestQueryConditions(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			in:       "status:failed,todo",
+			wantSQL:  "status::text = ANY($1)",
+			wantArgs: []any{[]string{"failed", "todo"}},
+		},
+		{
+			in:       "status:FAILED",
+			wantSQL:  "status::text = ANY($1)",
+			wantArgs: []any{[]string{"failed"}},
+		},
+		{
+			in:       "status!=succeeded",
+			wantSQL:  "NOT status::text = ANY($1)",
+			wantArgs: []any{[]string{"succeeded"}},
+		},
+		{
+			in:       "task:send_email",
+			wantSQL:  "task_name = $1",
+			wantArgs: []any{"send_email"},
+		},
+		{
+			in:       "task:send_*",
+			wantSQL:  "task_name LIKE $1",
+			wantArgs: []any{`send\_%`},
+		},
+		{
+			in:       "queue!=bulk*",
+			wantSQL:  "(queue_name IS NULL OR queue_name NOT LIKE $1)",
+			wantArgs: []any{"bulk%"},
+		},
+		{
+			in:       "lock!=a",
+			wantSQL:  "lock IS DISTINCT FROM $1",
+			wantArgs: []any{"a"},
+		},
+		{
+			in:       "id>5 attempts<=2 priority!=0",
+			wantSQL:  "id > $1 AND attempts <= $2 AND priority != $3",
+			wantArgs: []any{int64(5), int64(2), int64(0)},
+		},
+		{
+			in:       "scheduled<-1h",
+			wantSQL:  "scheduled_at < NOW() + $1::float8 * INTERVAL '1 second'",
+			wantArgs: []any{-3600.0},
+		},
+		{
+			in:       "scheduled>=+1d",
+			wantSQL:  "scheduled_at >= NOW() + $1::float8 * INTERVAL '1 second'",
+			wantArgs: []any{86400.0},
+		},
+		{
+			in:       "scheduled>2025-01-31",
+			wantSQL:  "scheduled_at > $1",
+			wantArgs: []any{time.Date(2025, time.January, 31, 0, 0, 0, 0, time.Local)},
+		},
+		{
+			in:       "args.order_id=1003",
+			wantSQL:  "(args #>> $1::text[]) = $2",
+			wantArgs: []any{[]string{"order_id"}, "1003"},
+		},
+		{
+			in:       `args.customer.tier!="gold plus"`,
+			wantSQL:  "(args #>> $1::text[]) IS DISTINCT FROM $2",
+			wantArgs: []any{[]string{"customer", "tier"}, "gold plus"},
+		},
+		{
+			in:       "args.name:a*",
+			wantSQL:  "(args #>> $1::text[]) LIKE $2",
+			wantArgs: []any{[]string{"name"}, "a%"},
+		},
+		{
+			in: "args.total>9.5",
+			wantSQL: `CASE WHEN jsonb_typeof(args #> $1::text[]) = 'number'
+		THEN (args #>> $1::text[])::numeric END > $2::numeric`,
+			wantArgs: []any{[]string{"total"}, 9.5},
+		},
+		{
+			in:       `args@>{"a":"}"}`,
+			wantSQL:  "args @> $1::jsonb",
+			wantArgs: []any{`{"a":"}"}`},
+		},
+		{
+			in:       "args:alice",
+			wantSQL:  "args::text ILIKE $1",
+			wantArgs: []any{"%alice%"},
+		},
+		{
+			in:       "send_email",
+			wantSQL:  "(task_name ILIKE $1 OR args::text ILIKE $1)",
+			wantArgs: []any{`%send\_email%`},
+		},
+		{
+			in:       "100%",
+			wantSQL:  "(task_name ILIKE $1 OR args::text ILIKE $1)",
+			wantArgs: []any{`%100\%%`},
+		},
+		{
+			in:       "alice@example.com",
+			wantSQL:  "(task_name ILIKE $1 OR args::text ILIKE $1)",
+			wantArgs: []any{"%alice@example.com%"},
+		},
+		{
+			in:       `"order:1003"`,
+			wantSQL:  "(task_name ILIKE $1 OR args::text ILIKE $1)",
+			wantArgs: []any{"%order:1003%"},
+		},
+		{
+			in:       `"a>=b" "x@>y" status:failed`,
+			wantSQL:  "(task_name ILIKE $1 OR args::text ILIKE $1) AND (task_name ILIKE $2 OR args::text ILIKE $2) AND status::text = ANY($3)",
+			wantArgs: []any{"%a>=b%", "%x@>y%", []string{"failed"}},
+		},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.in)
+		if err != nil {
+			t.Errorf("ParseQuery(%q): %v", tt.in, err)
+			continue
+		}
+		var args sqlArgs
+		got := strings.Join(q.conditions(&args), " AND ")
+		if got != tt.wantSQL {
+			t.Errorf("ParseQuery(%q) SQL:\n got %s\nwant %s", tt.in, got, tt.wantSQL)
+		}
+		if !reflect.DeepEqual([]any(args), tt.wantArgs) {
+			t.Errorf("ParseQuery(%q) args = %#v, want %#v", tt.in, []any(args), tt.wantArgs)
+		}
+	}
+}
+
+func TestQueryString(t *testing.T) {
+	q, err := ParseQuery("  status:failed  task:x ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := q.String(); got != "status:failed  task:x" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (*Query)(nil).String(); got != "" {
+		t.Errorf("nil String() = %q", got)
+	}
+}
+
+func TestQueryHighlights(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"status:failed task:x", nil},
+		{"args.order_id=1003", []string{"1003"}},
+		{"args.order_id!=1003 args.name=a*", nil},
+		{"args:alice bob", []string{"alice", "bob"}},
+		{`"order:1003"`, []string{"order:1003"}},
+		{`args@>{"order_id":1003,"tags":["vip"]}`, []string{"1003", "vip"}},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.in)
+		if err != nil {
+			t.Errorf("ParseQuery(%q): %v", tt.in, err)
+			continue
+		}
+		got := q.Highlights()
+		slices.Sort(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseQuery(%q).Highlights() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	if got := (*Query)(nil).Highlights(); got != nil {
+		t.Errorf("nil Highlights() = %q", got)
+	}
+}
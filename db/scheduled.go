@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ScheduleBucket counts future jobs due within a window.
+type ScheduleBucket struct {
+	Label  string
+	Within time.Duration // upper bound from now; 0 means unbounded
+	Count  int64
+}
+
+// scheduleWindows are the upper bounds of the schedule buckets.
+var scheduleWindows = []struct {
+	label  string
+	within time.Duration
+}{
+	{"next 5m", 5 * time.Minute},
+	{"next hour", time.Hour},
+	{"next day", 24 * time.Hour},
+	{"later", 0},
+}
+
+// CountScheduledJobs buckets the queue's todo jobs scheduled in the future
+// by how soon they are due. Each job is counted in the first bucket whose
+// window contains it.
+func CountScheduledJobs(ctx context.Context, c *Client, queue string) ([]ScheduleBucket, error) {
+	buckets := make([]ScheduleBucket, len(scheduleWindows))
+	dest := make([]any, len(scheduleWindows))
+	for i, w := range scheduleWindows {
+		buckets[i] = ScheduleBucket{Label: w.label, Within: w.within}
+		dest[i] = &buckets[i].Count
+	}
+
+	err := c.pool.QueryRow(ctx, fmt.Sprintf(`
+		SELECT COUNT(*) FILTER (WHERE scheduled_at <= NOW() + INTERVAL '5 minutes'),
+		       COUNT(*) FILTER (WHERE scheduled_at > NOW() + INTERVAL '5 minutes'
+		                          AND scheduled_at <= NOW() + INTERVAL '1 hour'),
+		       COUNT(*) FILTER (WHERE scheduled_at > NOW() + INTERVAL '1 hour'
+		                          AND scheduled_at <= NOW() + INTERVAL '1 day'),
+		       COUNT(*) FILTER (WHERE scheduled_at > NOW() + INTERVAL '1 day')
+		FROM procrastinate_jobs
+		WHERE %s
+		  AND status = 'todo'
+		  AND scheduled_at > NOW()`, queueMatch("queue_name", queue, 1)), queue).Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// ListScheduledJobs returns up to limit of the queue's todo jobs scheduled
+// in the future, soonest first.
+func ListScheduledJobs(ctx context.Context, c *Client, queue string, limit int) ([]Job, error) {
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM procrastinate_jobs
+		WHERE %s
+		  AND status = 'todo'
+		  AND scheduled_at > NOW()
+		ORDER BY scheduled_at, id
+		LIMIT $2`, c.schema.jobColumns(""), queueMatch("queue_name", queue, 1)), queue, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanJobs(rows)
+}
@@ -0,0 +1,335 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Schema describes the shape of the installed Procrastinate schema.
+// Queries adapt to it so one binary works across Procrastinate 2.x and 3.x.
+type Schema struct {
+	Version string // "3.x", "2.x" or "custom" for a mix of features
+
+	Statuses   []JobStatus     // values of procrastinate_job_status, in display order
+	EventTypes map[string]bool // values of procrastinate_job_event_type
+
+	HasAbortRequested bool // procrastinate_jobs.abort_requested (3.x)
+	HasWorkerID       bool // procrastinate_jobs.worker_id (3.x)
+	HasWorkers        bool // procrastinate_workers table with heartbeats (3.x)
+	HasEvents         bool // procrastinate_events table
+	EventsCascade     bool // deleting a job deletes its events (ON DELETE CASCADE)
+	HasPeriodicDefers bool // procrastinate_periodic_defers table
+	HasPeriodicID     bool // procrastinate_periodic_defers.periodic_id
+
+	// DeferFunction is the SQL function that enqueues jobs: deferJobsV1 (3.x),
+	// deferJob or deferJobNoPriority (2.x), or empty when none was found.
+	DeferFunction string
+
+	// LISTEN/NOTIFY channels. 3.x uses the "_v1" names with JSON payloads
+	// carrying the job id; 2.x payloads are plain text.
+	QueueChannelPrefix string
+	AnyQueueChannel    string
+	NotifyHasJobID     bool
+}
+
+// requiredJobColumns must exist in every supported version.
+var requiredJobColumns = []string{
+	"id", "queue_name", "task_name", "priority", "lock", "queueing_lock",
+	"args", "status", "scheduled_at", "attempts",
+}
+
+// defaultSchema is the Procrastinate 3.x layout from
+// scripts/setup_test_db.sql, whose channels a Listener not created through
+// a Client listens on.
+var defaultSchema = &Schema{
+	Version:            "3.x",
+	Statuses:           AllStatuses(),
+	EventTypes:         map[string]bool{},
+	HasAbortRequested:  true,
+	HasWorkerID:        true,
+	HasWorkers:         true,
+	HasEvents:          true,
+	EventsCascade:      true,
+	HasPeriodicDefers:  true,
+	HasPeriodicID:      true,
+	DeferFunction:      deferJobsV1,
+	QueueChannelPrefix: "procrastinate_queue_v1#",
+	AnyQueueChannel:    "procrastinate_any_queue_v1",
+	NotifyHasJobID:     true,
+}
+
+// DetectSchema inspects the database's Procrastinate tables, enums and
+// notify functions. It fails with a descriptive error when the schema is
+// missing or too old to be supported.
+func DetectSchema(ctx context.Context, pool *pgxpool.Pool) (*Schema, error) {
+	s := &Schema{EventTypes: make(map[string]bool)}
+
+	columns, err := tableColumns(ctx, pool, "procrastinate_jobs")
+	if err != nil {
+		return nil, fmt.Errorf("inspecting procrastinate_jobs: %w", err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("unsupported schema: table procrastinate_jobs not found (is Procrastinate installed in this database and search_path?)")
+	}
+	var missing []string
+	for _, c := range requiredJobColumns {
+		if !columns[c] {
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("unsupported Procrastinate schema: procrastinate_jobs is missing %s (only 2.x and 3.x are supported)",
+			strings.Join(missing, ", "))
+	}
+	s.HasAbortRequested = columns["abort_requested"]
+	s.HasWorkerID = columns["worker_id"]
+
+	workerColumns, err := tableColumns(ctx, pool, "procrastinate_workers")
+	if err != nil {
+		return nil, fmt.Errorf("inspecting procrastinate_workers: %w", err)
+	}
+	s.HasWorkers = s.HasWorkerID && workerColumns["last_heartbeat"]
+
+	eventColumns, err := tableColumns(ctx, pool, "procrastinate_events")
+	if err != nil {
+		return nil, fmt.Errorf("inspecting procrastinate_events: %w", err)
+	}
+	s.HasEvents = eventColumns["job_id"] && eventColumns["type"] && eventColumns["at"]
+	if s.HasEvents {
+		s.EventsCascade, err = eventsCascade(ctx, pool)
+		if err != nil {
+			return nil, fmt.Errorf("inspecting procrastinate_events foreign keys: %w", err)
+		}
+	}
+
+	periodicColumns, err := tableColumns(ctx, pool, "procrastinate_periodic_defers")
+	if err != nil {
+		return nil, fmt.Errorf("inspecting procrastinate_periodic_defers: %w", err)
+	}
+	s.HasPeriodicDefers = periodicColumns["task_name"] && periodicColumns["defer_timestamp"] && periodicColumns["job_id"]
+	s.HasPeriodicID = periodicColumns["periodic_id"]
+
+	statuses, err := enumValues(ctx, pool, "procrastinate_job_status")
+	if err != nil {
+		return nil, fmt.Errorf("reading procrastinate_job_status: %w", err)
+	}
+	if len(statuses) == 0 {
+		return nil, fmt.Errorf("unsupported Procrastinate schema: enum procrastinate_job_status not found")
+	}
+	s.Statuses = orderStatuses(statuses)
+
+	eventTypes, err := enumValues(ctx, pool, "procrastinate_job_event_type")
+	if err != nil {
+		return nil, fmt.Errorf("reading procrastinate_job_event_type: %w", err)
+	}
+	for _, t := range eventTypes {
+		s.EventTypes[t] = true
+	}
+
+	s.DeferFunction, err = deferFunction(ctx, pool)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting defer functions: %w", err)
+	}
+
+	v1, err := usesV1Channels(ctx, pool)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting notify functions: %w", err)
+	}
+	if v1 {
+		s.QueueChannelPrefix = "procrastinate_queue_v1#"
+		s.AnyQueueChannel = "procrastinate_any_queue_v1"
+		s.NotifyHasJobID = true
+	} else {
+		s.QueueChannelPrefix = "procrastinate_queue#"
+		s.AnyQueueChannel = "procrastinate_any_queue"
+	}
+
+	switch {
+	case s.HasAbortRequested && s.HasWorkers && v1:
+		s.Version = "3.x"
+	case !s.HasAbortRequested && !s.HasWorkerID && !v1 && s.HasStatus(StatusAborting):
+		s.Version = "2.x"
+	default:
+		s.Version = "custom"
+	}
+
+	return s, nil
+}
+
+// HasStatus reports whether the status exists in the job status enum.
+func (s *Schema) HasStatus(status JobStatus) bool {
+	return slices.Contains(s.Statuses, status)
+}
+
+// HasEventType reports whether events of this type can be recorded.
+func (s *Schema) HasEventType(eventType string) bool {
+	return s.HasEvents && (len(s.EventTypes) == 0 || s.EventTypes[eventType])
+}
+
+// jobColumns returns the select list scanned by scanJobs, qualified with
+// alias when non-empty. Columns missing from older schemas are filled with
+// constants so the scan order never changes.
+func (s *Schema) jobColumns(alias string) string {
+	p := ""
+	if alias != "" {
+		p = alias + "."
+	}
+	cols := fmt.Sprintf("%[1]sid, %[1]squeue_name, %[1]stask_name, %[1]spriority, %[1]slock, %[1]squeueing_lock, "+
+		"%[1]sargs, %[1]sstatus, %[1]sscheduled_at, %[1]sattempts", p)
+	if s.HasAbortRequested {
+		cols += ", " + p + "abort_requested"
+	} else {
+		cols += ", false AS abort_requested"
+	}
+	if s.HasWorkerID {
+		cols += ", " + p + "worker_id"
+	} else {
+		cols += ", NULL::bigint AS worker_id"
+	}
+	return cols
+}
+
+// orderStatuses sorts enum values into AllStatuses display order, keeping
+// any statuses this tool does not know about at the end.
+func orderStatuses(values []string) []JobStatus {
+	present := make(map[JobStatus]bool, len(values))
+	for _, v := range values {
+		present[JobStatus(v)] = true
+	}
+
+	var ordered []JobStatus
+	for _, st := range AllStatuses() {
+		if present[st] {
+			ordered = append(ordered, st)
+			delete(present, st)
+		}
+	}
+	for _, v := range values {
+		if present[JobStatus(v)] {
+			ordered = append(ordered, JobStatus(v))
+		}
+	}
+	return ordered
+}
+
+func tableColumns(ctx context.Context, pool *pgxpool.Pool, table string) (map[string]bool, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT column_name
+		FROM information_schema.columns
+		WHERE table_name = $1
+		  AND table_schema = ANY(current_schemas(false))`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return nil, err
+		}
+		columns[c] = true
+	}
+	return columns, rows.Err()
+}
+
+func enumValues(ctx context.Context, pool *pgxpool.Pool, typeName string) ([]string, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT e.enumlabel
+		FROM pg_enum e
+		JOIN pg_type t ON t.oid = e.enumtypid
+		WHERE t.typname = $1
+		  AND pg_type_is_visible(t.oid)
+		ORDER BY e.enumsortorder`, typeName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+// eventsCascade reports whether procrastinate_events references
+// procrastinate_jobs with ON DELETE CASCADE.
+func eventsCascade(ctx context.Context, pool *pgxpool.Pool) (bool, error) {
+	var cascade bool
+	err := pool.QueryRow(ctx, `
+		SELECT EXISTS (
+		  SELECT 1
+		  FROM pg_constraint
+		  WHERE contype = 'f'
+		    AND conrelid = to_regclass('procrastinate_events')
+		    AND confrelid = to_regclass('procrastinate_jobs')
+		    AND confdeltype = 'c'
+		)`).Scan(&cascade)
+	return cascade, err
+}
+
+// deferFunction returns the newest defer function installed, or "".
+func deferFunction(ctx context.Context, pool *pgxpool.Pool) (string, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT proname, pronargs
+		FROM pg_proc
+		WHERE proname IN ('procrastinate_defer_jobs_v1', 'procrastinate_defer_job')
+		  AND pg_function_is_visible(oid)`)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	found := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		var nargs int16
+		if err := rows.Scan(&name, &nargs); err != nil {
+			return "", err
+		}
+		switch {
+		case name == "procrastinate_defer_jobs_v1":
+			found[deferJobsV1] = true
+		case nargs == 7:
+			found[deferJob] = true
+		case nargs == 6:
+			found[deferJobNoPriority] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	for _, f := range []string{deferJobsV1, deferJob, deferJobNoPriority} {
+		if found[f] {
+			return f, nil
+		}
+	}
+	return "", nil
+}
+
+// usesV1Channels reports whether the notify trigger functions publish on the
+// 3.x "_v1" channels. Defaults to true when no notify function is found.
+func usesV1Channels(ctx context.Context, pool *pgxpool.Pool) (bool, error) {
+	var v1, legacy bool
+	err := pool.QueryRow(ctx, `
+		SELECT
+		  COALESCE(bool_or(prosrc LIKE '%procrastinate_any_queue_v1%'), false),
+		  COALESCE(bool_or(prosrc LIKE '%procrastinate_any_queue%'
+		               AND prosrc NOT LIKE '%procrastinate_any_queue_v1%'), false)
+		FROM pg_proc
+		WHERE proname LIKE 'procrastinate_notify%'`).Scan(&v1, &legacy)
+	if err != nil {
+		return false, err
+	}
+	return v1 || !legacy, nil
+}
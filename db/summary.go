@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// Summary is a database-wide health snapshot across all queues, used by
+// the fleet dashboard.
+type Summary struct {
+	Todo           int64
+	Doing          int64
+	FailedLastHour *int64 // nil when the schema records no events
+	Orphaned       int64
+}
+
+// Summarize returns the todo backlog, doing count, jobs failed in the last
+// hour and orphaned job count across every queue.
+func Summarize(ctx context.Context, c *Client, orphanThreshold time.Duration) (*Summary, error) {
+	var s Summary
+	err := c.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE status = 'todo'),
+		       COUNT(*) FILTER (WHERE status = 'doing')
+		FROM procrastinate_jobs
+		WHERE status IN ('todo', 'doing')`).Scan(&s.Todo, &s.Doing)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.schema.HasEventType("failed") {
+		var failed int64
+		err := c.pool.QueryRow(ctx, `
+			SELECT COUNT(DISTINCT job_id)
+			FROM procrastinate_events
+			WHERE type = 'failed'
+			  AND at > NOW() - INTERVAL '1 hour'`).Scan(&failed)
+		if err != nil {
+			return nil, err
+		}
+		s.FailedLastHour = &failed
+	}
+
+	s.Orphaned, err = CountOrphanedJobs(ctx, c, AllQueues, orphanThreshold)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
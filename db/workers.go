@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoWorkersTable is returned when the schema predates procrastinate_workers.
+var ErrNoWorkersTable = errors.New("this Procrastinate schema has no procrastinate_workers table (added in 3.x)")
+
+// Worker represents a row from procrastinate_workers with the jobs it holds.
+type Worker struct {
+	ID            int64
+	LastHeartbeat *time.Time
+	DoingJobIDs   []int64 // jobs currently in 'doing' assigned to this worker
+}
+
+// ListWorkers returns every registered worker with its doing jobs, ordered
+// by id.
+func ListWorkers(ctx context.Context, c *Client) ([]Worker, error) {
+	if !c.schema.HasWorkers {
+		return nil, ErrNoWorkersTable
+	}
+
+	rows, err := c.pool.Query(ctx, `
+		SELECT w.id, w.last_heartbeat,
+		       COALESCE(array_agg(j.id ORDER BY j.id) FILTER (WHERE j.id IS NOT NULL), '{}')
+		FROM procrastinate_workers w
+		LEFT JOIN procrastinate_jobs j ON j.worker_id = w.id AND j.status = 'doing'
+		GROUP BY w.id, w.last_heartbeat
+		ORDER BY w.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workers []Worker
+	for rows.Next() {
+		var w Worker
+		if err := rows.Scan(&w.ID, &w.LastHeartbeat, &w.DoingJobIDs); err != nil {
+			return nil, err
+		}
+		workers = append(workers, w)
+	}
+	return workers, rows.Err()
+}
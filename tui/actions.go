@@ -0,0 +1,424 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+// selectedJob returns the job an action applies to: the job open in the
+// detail pane when it has focus, otherwise the highlighted sidebar row.
+func (a *App) selectedJob() *db.Job {
+	if a.showDetail && a.focus == focusDetail {
+		return a.detailView.job
+	}
+	if a.focus == focusSidebar {
+		return a.sidebar.SelectedJob()
+	}
+	return nil
+}
+
+// openConfirm shows a yes/no overlay. onConfirm runs only if the user accepts.
+func (a *App) openConfirm(title string, lines []string, onConfirm func() tea.Cmd) {
+	a.overlay = overlayConfirm
+	a.confirmTitle = title
+	a.confirmLines = lines
+	a.confirmFn = onConfirm
+}
+
+func (a *App) confirmRetry() tea.Cmd {
+	job := a.selectedJob()
+	if job == nil {
+		return nil
+	}
+	if !job.Status.Retryable() {
+		return a.showToast(fmt.Sprintf(
+			"Job #%d is %s; only failed, cancelled or aborted jobs can be retried", job.ID, job.Status), true)
+	}
+
+	id := job.ID
+	a.openConfirm("Retry Job", []string{
+		fmt.Sprintf("Move job #%d (%s) from %s back to todo?", job.ID, job.TaskName, job.Status),
+	}, func() tea.Cmd {
+		return a.runJobAction("Retried", db.RetryJobs, []int64{id})
+	})
+	return nil
+}
+
+func (a *App) confirmCancel() tea.Cmd {
+	job := a.selectedJob()
+	if job == nil {
+		return nil
+	}
+	if !job.Status.Cancellable() {
+		return a.showToast(fmt.Sprintf(
+			"Job #%d is %s; only todo jobs can be cancelled (use abort for doing jobs)", job.ID, job.Status), true)
+	}
+
+	id := job.ID
+	a.openConfirm("Cancel Job", []string{
+		fmt.Sprintf("Cancel todo job #%d (%s)?", job.ID, job.TaskName),
+	}, func() tea.Cmd {
+		return a.runJobAction("Cancelled", db.CancelJobs, []int64{id})
+	})
+	return nil
+}
+
+func (a *App) confirmAbort() tea.Cmd {
+	job := a.selectedJob()
+	if job == nil {
+		return nil
+	}
+	if !job.Status.Abortable() {
+		return a.showToast(fmt.Sprintf(
+			"Job #%d is %s; only doing jobs can be aborted", job.ID, job.Status), true)
+	}
+
+	id := job.ID
+	a.openConfirm("Abort Job", []string{
+		fmt.Sprintf("Request abort of running job #%d (%s)?", job.ID, job.TaskName),
+		"The worker stops the job the next time it checks for abort requests.",
+	}, func() tea.Cmd {
+		return a.runJobAction("Abort requested for", db.AbortJobs, []int64{id})
+	})
+	return nil
+}
+
+// runJobAction runs a bulk db action in the background and reports the
+// outcome as a jobActionMsg.
+func (a *App) runJobAction(verb string, run func(context.Context, *db.Client, []int64) ([]int64, error), ids []int64) tea.Cmd {
+	if a.dbClient == nil {
+		return nil
+	}
+	client := a.dbClient
+	gen := a.fetchGen
+	return func() tea.Msg {
+		done, err := run(context.Background(), client, ids)
+		return jobActionMsg{verb: verb, requested: ids, done: done, err: err, gen: gen}
+	}
+}
+
+// orphanAction is a bulk action offered for orphaned jobs.
+type orphanAction struct {
+	label string
+	title string
+	verb  string
+	run   func(context.Context, *db.Client, []int64, time.Duration) ([]int64, error)
+}
+
+var orphanActions = []orphanAction{
+	{label: "Requeue (reset to todo)", title: "Requeue", verb: "Requeued", run: db.RequeueJobs},
+	{label: "Mark failed", title: "Mark failed", verb: "Failed", run: db.FailJobs},
+	{label: "Cancel", title: "Cancel", verb: "Cancelled", run: db.ForceCancelJobs},
+}
+
+// handleOrphanedKey handles selection and bulk actions on the Orphaned tab.
+// Returns false for keys it does not handle.
+func (a *App) handleOrphanedKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch {
+	case key.Matches(msg, a.keys.Up):
+		a.orphanedView.MoveCursor(-1)
+	case key.Matches(msg, a.keys.Down):
+		a.orphanedView.MoveCursor(1)
+	case key.Matches(msg, a.keys.Select):
+		a.orphanedView.ToggleSelected()
+	case key.Matches(msg, a.keys.SelectAll):
+		a.orphanedView.ToggleAll()
+	case key.Matches(msg, a.keys.Enter):
+		if a.connected && !a.readOnly && len(a.orphanedView.Targets()) > 0 {
+			a.openOrphanActionPicker()
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+func (a *App) openOrphanActionPicker() {
+	labels := make([]string, len(orphanActions))
+	for i, action := range orphanActions {
+		labels[i] = action.label
+	}
+	a.overlay = overlayOrphanAction
+	a.pickerItems = labels
+	a.pickerIndex = 0
+}
+
+// confirmOrphanAction asks for confirmation, listing every job ID the
+// chosen bulk action will change.
+func (a *App) confirmOrphanAction(index int) tea.Cmd {
+	action := orphanActions[index]
+	targets := a.orphanedView.Targets()
+	if len(targets) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(targets))
+	for i, j := range targets {
+		ids[i] = j.ID
+	}
+
+	lines := []string{fmt.Sprintf("%s %d orphaned job(s) in one transaction:", action.title, len(ids)), ""}
+	lines = append(lines, wrapJobIDs(ids, 8)...)
+
+	// The action re-checks each job is still orphaned, so one a live worker
+	// picked up since the list loaded is skipped.
+	threshold := a.config.OrphanThreshold
+	run := func(ctx context.Context, client *db.Client, ids []int64) ([]int64, error) {
+		return action.run(ctx, client, ids, threshold)
+	}
+	a.openConfirm(action.title+" Orphaned Jobs", lines, func() tea.Cmd {
+		a.orphanedView.ClearSelection()
+		return a.runJobAction(action.verb, run, ids)
+	})
+	return nil
+}
+
+// wrapJobIDs formats IDs as "#1 #2 ..." with perLine IDs per line.
+func wrapJobIDs(ids []int64, perLine int) []string {
+	var lines []string
+	for start := 0; start < len(ids); start += perLine {
+		end := min(start+perLine, len(ids))
+		parts := make([]string, 0, end-start)
+		for _, id := range ids[start:end] {
+			parts = append(parts, fmt.Sprintf("#%d", id))
+		}
+		lines = append(lines, strings.Join(parts, " "))
+	}
+	return lines
+}
+
+// handleJobAction reports an action's outcome and refreshes affected data.
+func (a *App) handleJobAction(msg jobActionMsg) []tea.Cmd {
+	if msg.err != nil {
+		a.lastError = msg.err
+		return []tea.Cmd{a.showToast(fmt.Sprintf("%s failed: %v", msg.verb, msg.err), true)}
+	}
+
+	var toast tea.Cmd
+	switch {
+	case len(msg.done) == 0:
+		toast = a.showToast("No jobs changed: status changed in the meantime", true)
+	case len(msg.requested) == 1:
+		toast = a.showToast(fmt.Sprintf("%s job #%d", msg.verb, msg.done[0]), false)
+	case len(msg.done) < len(msg.requested):
+		toast = a.showToast(fmt.Sprintf("%s %d of %d jobs", msg.verb, len(msg.done), len(msg.requested)), true)
+	default:
+		toast = a.showToast(fmt.Sprintf("%s %d jobs", msg.verb, len(msg.done)), false)
+	}
+
+	cmds := []tea.Cmd{toast, a.fetchJobs(), a.fetchActiveTabData()}
+	if a.showDetail && a.detailView.job != nil {
+		cmds = append(cmds, a.refreshJobDetail(a.detailView.job.ID))
+	}
+	return cmds
+}
+
+// showToast displays a transient message and schedules its dismissal.
+func (a *App) showToast(text string, isErr bool) tea.Cmd {
+	a.toast = text
+	a.toastErr = isErr
+	return a.clearToastCmd()
+}
+
+// openDeferForm shows the defer form for the current queue, or the
+// connection's default queue when viewing all queues.
+func (a *App) openDeferForm() tea.Cmd {
+	queue := a.currentQueue
+	if queue == db.AllQueues {
+		if conn, err := a.config.GetConnection(a.currentConn); err == nil {
+			queue = conn.DefaultQueue
+		}
+	}
+	a.overlay = overlayDefer
+	return a.deferForm.Open(queue)
+}
+
+// handleDeferKey edits the defer form and, on enter, defers the job.
+func (a *App) handleDeferKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		a.overlay = overlayNone
+		a.deferForm.Close()
+		return a, nil
+
+	case tea.KeyTab, tea.KeyDown:
+		return a, a.deferForm.MoveFocus(1)
+
+	case tea.KeyShiftTab, tea.KeyUp:
+		return a, a.deferForm.MoveFocus(-1)
+
+	case tea.KeyEnter:
+		req, err := a.deferForm.Request(time.Now())
+		if err != nil {
+			a.deferForm.SetError(err)
+			return a, nil
+		}
+		a.overlay = overlayNone
+		a.deferForm.Close()
+		return a, a.runDefer(req)
+	}
+
+	return a, a.deferForm.Update(msg)
+}
+
+// runDefer defers a job in the background and reports the outcome as a
+// deferResultMsg.
+func (a *App) runDefer(req db.DeferRequest) tea.Cmd {
+	if a.dbClient == nil {
+		return nil
+	}
+	client := a.dbClient
+	gen := a.fetchGen
+	return func() tea.Msg {
+		id, err := db.DeferJob(context.Background(), client, req)
+		return deferResultMsg{id: id, queue: req.Queue, err: err, gen: gen}
+	}
+}
+
+// handleDeferResult reports a deferred job and refreshes the job list.
+// Queueing lock conflicts are shown as is; they name the job holding the lock.
+func (a *App) handleDeferResult(msg deferResultMsg) []tea.Cmd {
+	var lockErr *db.QueueingLockError
+	if errors.As(msg.err, &lockErr) {
+		return []tea.Cmd{a.showToast(lockErr.Error(), true)}
+	}
+	if msg.err != nil {
+		a.lastError = msg.err
+		return []tea.Cmd{a.showToast(fmt.Sprintf("Defer failed: %v", msg.err), true)}
+	}
+	toast := a.showToast(fmt.Sprintf("Deferred job #%d to queue %s", msg.id, msg.queue), false)
+	return []tea.Cmd{toast, a.fetchJobs(), a.fetchActiveTabData()}
+}
+
+// purgeChoices are the purge picker's options; the second includes failed jobs.
+var purgeChoices = []string{
+	"Succeeded, cancelled and aborted jobs",
+	"Succeeded, cancelled, aborted and failed jobs",
+}
+
+// openPurgePicker asks which finished jobs to purge from the current queue,
+// or from every queue when viewing all queues.
+func (a *App) openPurgePicker() {
+	a.overlay = overlayPurgePicker
+	a.pickerItems = purgeChoices
+	a.pickerIndex = 0
+}
+
+// handlePurgeKey reads the purge age and, on enter, counts the jobs it
+// would delete.
+func (a *App) handlePurgeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		a.overlay = overlayNone
+		a.promptInput.Blur()
+		return a, nil
+
+	case tea.KeyEnter:
+		age, err := db.ParseAge(a.promptInput.Value())
+		if err != nil {
+			a.promptErr = err
+			return a, nil
+		}
+		a.overlay = overlayNone
+		a.promptInput.Blur()
+		return a, a.previewPurge(db.PurgeFilter{
+			Queue:         a.currentQueue,
+			OlderThan:     age,
+			IncludeFailed: a.purgeIncludeFailed,
+		})
+	}
+
+	var cmd tea.Cmd
+	a.promptInput, cmd = a.promptInput.Update(msg)
+	a.promptErr = nil
+	return a, cmd
+}
+
+// previewPurge counts the jobs a purge would delete, as a purgePreviewMsg.
+func (a *App) previewPurge(filter db.PurgeFilter) tea.Cmd {
+	if a.dbClient == nil {
+		return nil
+	}
+	client := a.dbClient
+	gen := a.fetchGen
+	return func() tea.Msg {
+		counts, cutoff, err := db.PurgePreview(context.Background(), client, filter)
+		return purgePreviewMsg{filter: filter, counts: counts, cutoff: cutoff, err: err, gen: gen}
+	}
+}
+
+// confirmPurge shows the dry-run counts by queue and status and deletes
+// the jobs only if the user accepts.
+func (a *App) confirmPurge(msg purgePreviewMsg) tea.Cmd {
+	if msg.err != nil {
+		a.lastError = msg.err
+		return a.showToast(fmt.Sprintf("Purge failed: %v", msg.err), true)
+	}
+
+	var total int64
+	var lines []string
+	for _, c := range msg.counts {
+		lines = append(lines, fmt.Sprintf("%-20s %-10s %8d", truncate(c.Queue, 20), c.Status, c.Jobs))
+		total += c.Jobs
+	}
+	age := formatDuration(msg.filter.OlderThan)
+	if total == 0 {
+		return a.showToast(fmt.Sprintf("Nothing to purge: no matching jobs older than %s", age), false)
+	}
+
+	lines = append([]string{
+		fmt.Sprintf("Delete %d job(s) whose latest event is older than %s?", total, age),
+		"",
+	}, lines...)
+	lines = append(lines, "", fmt.Sprintf("Jobs are deleted %d at a time, with their events.", db.DefaultPurgeBatch))
+
+	filter, cutoff := msg.filter, msg.cutoff
+	a.openConfirm("Purge Finished Jobs", lines, func() tea.Cmd {
+		return a.runPurge(filter, cutoff)
+	})
+	return nil
+}
+
+// runPurge deletes the jobs the preview counted, in batches, and reports
+// the outcome as a purgeResultMsg.
+func (a *App) runPurge(filter db.PurgeFilter, cutoff time.Time) tea.Cmd {
+	if a.dbClient == nil {
+		return nil
+	}
+	client := a.dbClient
+	conn := a.currentConn
+	gen := a.fetchGen
+	return func() tea.Msg {
+		deleted, err := db.PurgeJobs(context.Background(), client, filter, cutoff, db.DefaultPurgeBatch)
+		return purgeResultMsg{conn: conn, deleted: deleted, err: err, gen: gen}
+	}
+}
+
+// handlePurgeResult reports a purge, even one that finished after switching
+// queue or connection, and refreshes the job list if it is still showing
+// the purged queue.
+func (a *App) handlePurgeResult(msg purgeResultMsg) []tea.Cmd {
+	where := ""
+	if msg.conn != a.currentConn {
+		where = " on " + msg.conn
+	}
+	if msg.err != nil {
+		if msg.gen == a.fetchGen {
+			a.lastError = msg.err
+		}
+		return []tea.Cmd{a.showToast(fmt.Sprintf("Purge%s failed after deleting %d job(s): %v", where, msg.deleted, msg.err), true)}
+	}
+	toast := a.showToast(fmt.Sprintf("Purged %d job(s)%s", msg.deleted, where), false)
+	if msg.gen != a.fetchGen {
+		return []tea.Cmd{toast}
+	}
+	return []tea.Cmd{toast, a.fetchJobs(), a.fetchActiveTabData()}
+}
@@ -2,12 +2,18 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jackc/pgx/v5"
 	"github.com/matthewmyrick/procrastinate-cli/config"
 	"github.com/matthewmyrick/procrastinate-cli/db"
 )
@@ -15,6 +21,17 @@ import (
 const (
 	minWidth  = 80
 	minHeight = 24
+
+	// notifyCoalesceWindow batches bursts of NOTIFYs into a single fetch.
+	notifyCoalesceWindow = 250 * time.Millisecond
+
+	// allQueuesLabel names the all-queues pseudo-queue in the queue picker.
+	allQueuesLabel = "(all queues)"
+
+	// Fleet dashboard polls give up on a connection after fleetPollTimeout,
+	// and on opening its first connection after fleetConnectTimeout.
+	fleetPollTimeout    = 10 * time.Second
+	fleetConnectTimeout = 5 * time.Second
 )
 
 type focusPane int
@@ -32,6 +49,13 @@ const (
 	overlayConnPicker
 	overlayFilterPicker
 	overlayHelp
+	overlayConfirm
+	overlayOrphanAction
+	overlayQuery
+	overlayGoTo
+	overlayDefer
+	overlayPurgePicker
+	overlayPurge
 )
 
 // App is the root Bubble Tea model.
@@ -39,13 +63,16 @@ type App struct {
 	config *config.Config
 
 	// DB state — nil until connected
-	dbClient  *db.Client
-	listener  *db.Listener
-	connected bool
+	dbClient      *db.Client
+	listener      *db.Listener
+	listenerState db.ListenerState
+	connected     bool
 
 	currentQueue  string
 	currentConn   string
 	queueOverride string // from --queue flag, empty means use connection default
+	readOnly      bool   // current connection is read-only; mutating actions are disabled
+	environment   string // current connection's environment label (e.g. production)
 	focus         focusPane
 	overlay       overlayMode
 	width         int
@@ -53,16 +80,28 @@ type App struct {
 	ready         bool
 	lastError     error
 	toast         string
+	toastErr      bool
 	showDetail    bool   // true = right pane shows job detail, false = dashboard tabs
 	fetchGen      uint64 // incremented on connection/queue change; stale results are ignored
 
+	// Job IDs from notifications waiting for the current coalescing window
+	pendingNotified    map[int64]bool
+	pendingFullRefresh bool // a notification without a job id arrived
+	flushScheduled     bool
+
 	// Child components
-	sidebar      Sidebar
-	tabBar       TabBar
-	statusView   StatusView
-	liveView     LiveView
-	orphanedView OrphanedView
-	detailView   DetailView
+	sidebar       Sidebar
+	tabBar        TabBar
+	statusView    StatusView
+	liveView      LiveView
+	orphanedView  OrphanedView
+	workersView   WorkersView
+	periodicView  PeriodicView
+	locksView     LocksView
+	scheduledView ScheduledView
+	metricsView   MetricsView
+	failuresView  FailuresView
+	detailView    DetailView
 
 	// Picker state
 	queues        []string
@@ -71,15 +110,36 @@ type App struct {
 	switchConnFn  func(string) tea.Cmd
 	switchQueueFn func(string) tea.Cmd
 
+	// Fleet dashboard: one extra pool per configured connection other than
+	// the current one, kept open once the dashboard has been shown and
+	// handed over when drilling into that connection
+	fleetMode    bool
+	fleetTicking bool
+	fleetView    FleetView
+	fleetClients map[string]*db.Client
+	fleetPolling map[string]bool // connections with a poll in flight
+
+	// Text prompt state, shared by the query, go-to-job and purge prompts
+	promptInput        textinput.Model
+	promptErr          error // why the last submitted value was rejected
+	deferForm          DeferForm
+	purgeIncludeFailed bool // chosen in the purge picker, before the age prompt
+
+	// Confirm overlay state
+	confirmTitle string
+	confirmLines []string
+	confirmFn    func() tea.Cmd
+
 	keys KeyMap
 }
 
 // connectedMsg is sent after a connection attempt completes.
 type connectedMsg struct {
-	client   *db.Client
-	listener *db.Listener
-	queue    string
-	err      error
+	client        *db.Client
+	listener      *db.Listener
+	listenerState db.ListenerState // Reconnecting when the first LISTEN failed
+	queue         string
+	err           error
 }
 
 // NewApp creates the root TUI model. No DB connection yet — that happens on Init.
@@ -90,7 +150,7 @@ func NewApp(cfg *config.Config, connName, queueOverride string) *App {
 		initialQueue = queueOverride
 	}
 
-	return &App{
+	app := &App{
 		config:        cfg,
 		currentConn:   connName,
 		currentQueue:  initialQueue,
@@ -102,17 +162,49 @@ func NewApp(cfg *config.Config, connName, queueOverride string) *App {
 		statusView:    NewStatusView(),
 		liveView:      NewLiveView(),
 		orphanedView:  NewOrphanedView(),
+		workersView:   NewWorkersView(cfg.OrphanThreshold),
+		periodicView:  NewPeriodicView(),
+		locksView:     NewLocksView(),
+		scheduledView: NewScheduledView(),
+		metricsView:   NewMetricsView(),
+		failuresView:  NewFailuresView(),
+		fleetView:     NewFleetView(cfg.Connections),
+		fleetClients:  make(map[string]*db.Client),
+		fleetPolling:  make(map[string]bool),
 		detailView:    NewDetailView(),
+		promptInput:   newPromptInput(),
+		deferForm:     NewDeferForm(),
 		keys:          DefaultKeyMap(),
 	}
+	app.applyConnectionMode(conn)
+	return app
+}
+
+// applyConnectionMode records the connection's environment and enables or
+// disables every mutating key binding according to its read-only setting.
+func (a *App) applyConnectionMode(conn *config.Connection) {
+	a.readOnly = conn.IsReadOnly()
+	a.environment = conn.Environment
+	for _, b := range a.keys.MutatingKeys() {
+		b.SetEnabled(!a.readOnly)
+	}
+}
+
+// ShowFleet starts the app on the fleet dashboard.
+func (a *App) ShowFleet() {
+	a.fleetMode = true
 }
 
 func (a *App) Init() tea.Cmd {
-	return a.connectCmd(a.currentConn, a.currentQueue)
+	if a.fleetMode {
+		return tea.Batch(a.connectCmd(a.currentConn, a.currentQueue, nil), a.openFleet())
+	}
+	return a.connectCmd(a.currentConn, a.currentQueue, nil)
 }
 
-// connectCmd attempts to connect to a database in the background.
-func (a *App) connectCmd(connName, queue string) tea.Cmd {
+// connectCmd attempts to connect to a database in the background, reusing
+// client when it is already connected to it.
+func (a *App) connectCmd(connName, queue string, client *db.Client) tea.Cmd {
 	cfg := a.config
 	return func() tea.Msg {
 		conn, err := cfg.GetConnection(connName)
@@ -120,22 +212,22 @@ func (a *App) connectCmd(connName, queue string) tea.Cmd {
 			return connectedMsg{err: err}
 		}
 
-		client, err := db.NewClient(config.ConnString(conn))
-		if err != nil {
-			return connectedMsg{err: fmt.Errorf("connect to %s: %w", connName, err)}
+		if client == nil {
+			client, err = db.NewClient(context.Background(), config.ConnString(conn))
+			if err != nil {
+				return connectedMsg{err: fmt.Errorf("connect to %s: %w", connName, err)}
+			}
 		}
 
-		// Try to set up listener (non-fatal if it fails)
-		var listener *db.Listener
-		listenerConn, err := client.NewListenerConn(context.Background())
-		if err == nil {
-			listener = db.NewListener(listenerConn, queue)
-			if startErr := listener.Start(context.Background()); startErr != nil {
-				listener = nil
-			}
+		// A listener that fails to start keeps retrying in the background;
+		// polling covers for it meanwhile.
+		listener := client.NewListener(queue)
+		state := db.ListenerConnected
+		if err := listener.Start(context.Background()); err != nil {
+			state = db.ListenerReconnecting
 		}
 
-		return connectedMsg{client: client, listener: listener, queue: queue}
+		return connectedMsg{client: client, listener: listener, listenerState: state, queue: queue}
 	}
 }
 
@@ -158,31 +250,42 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case connectedMsg:
 		if msg.err != nil {
-			a.toast = fmt.Sprintf("Connection failed: %s", a.currentConn)
 			a.connected = false
 			a.dbClient = nil
 			a.listener = nil
-			cmds = append(cmds, a.clearToastCmd())
+			a.lastError = msg.err
+			cmds = append(cmds, a.showToast(fmt.Sprintf("Connection failed: %s", a.currentConn), true))
 		} else {
 			a.dbClient = msg.client
 			a.listener = msg.listener
+			a.listenerState = msg.listenerState
 			a.connected = true
 			a.lastError = nil
+			a.statusView.SetStatuses(msg.client.Schema().Statuses)
+			a.sidebar.SetStatuses(msg.client.Schema().Statuses)
 			// Start fetching data and polling
 			cmds = append(cmds,
 				a.fetchJobs(), a.fetchStatusCounts(), a.fetchQueues(),
-				a.tickCmd(), a.listenCmd(),
+				a.tickCmd(), a.listenCmd(), a.listenerStateCmd(),
 			)
 		}
 
 	case jobsLoadedMsg:
-		if msg.gen != a.fetchGen {
-			break // stale result from old connection/queue
+		if msg.gen != a.fetchGen || msg.listing != a.sidebar.Listing() {
+			break // stale result from old connection, queue or filter
 		}
 		if msg.err != nil {
 			a.lastError = msg.err
+			if msg.appendPage {
+				a.sidebar.loadingMore = false
+			}
 		} else {
-			cmd := a.sidebar.SetJobs(msg.jobs)
+			var cmd tea.Cmd
+			if msg.appendPage {
+				cmd = a.sidebar.AppendPage(msg.jobs, msg.limit)
+			} else {
+				cmd = a.sidebar.RefreshFirstPage(msg.jobs, msg.limit, msg.total)
+			}
 			a.lastError = nil
 			if cmd != nil {
 				cmds = append(cmds, cmd)
@@ -197,6 +300,12 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.lastError = msg.err
 		} else {
 			a.statusView.SetCounts(msg.counts)
+			a.statusView.SetTrend(msg.trend)
+			if msg.queueCounts != nil {
+				a.statusView.SetQueueCounts(msg.queueCounts)
+			} else {
+				a.statusView.SetTaskCounts(msg.taskCounts)
+			}
 			a.lastError = nil
 		}
 
@@ -222,31 +331,246 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.lastError = nil
 		}
 
-	case queuesLoadedMsg:
+	case workersLoadedMsg:
+		if msg.gen != a.fetchGen {
+			break
+		}
+		if errors.Is(msg.err, db.ErrNoWorkersTable) {
+			a.workersView.SetWorkers(nil, msg.err)
+		} else if msg.err != nil {
+			a.lastError = msg.err
+		} else {
+			a.workersView.SetWorkers(msg.workers, nil)
+			a.lastError = nil
+		}
+
+	case periodicDefersMsg:
+		if msg.gen != a.fetchGen {
+			break
+		}
+		if errors.Is(msg.err, db.ErrNoPeriodicTable) {
+			a.periodicView.SetDefers(nil, msg.err)
+		} else if msg.err != nil {
+			a.lastError = msg.err
+		} else {
+			a.periodicView.SetDefers(msg.defers, nil)
+			a.lastError = nil
+		}
+
+	case locksLoadedMsg:
 		if msg.gen != a.fetchGen {
 			break
 		}
 		if msg.err != nil {
 			a.lastError = msg.err
 		} else {
-			a.queues = msg.queues
+			a.locksView.SetLocks(msg.groups, msg.queueingLocks)
+			a.lastError = nil
 		}
 
-	case jobDetailMsg:
+	case scheduledJobsMsg:
 		if msg.gen != a.fetchGen {
 			break
 		}
 		if msg.err != nil {
 			a.lastError = msg.err
 		} else {
+			a.scheduledView.SetJobs(msg.buckets, msg.jobs)
+			a.lastError = nil
+		}
+
+	case metricsLoadedMsg:
+		if msg.gen != a.fetchGen || msg.request != a.metricsView.Request() {
+			break
+		}
+		if errors.Is(msg.err, db.ErrNoEvents) {
+			a.metricsView.SetStats(nil, msg.err)
+		} else if msg.err != nil {
+			a.lastError = msg.err
+		} else {
+			a.metricsView.SetStats(msg.stats, nil)
+			a.lastError = nil
+		}
+
+	case failureGroupsMsg:
+		if msg.gen != a.fetchGen {
+			break
+		}
+		if msg.err != nil {
+			a.lastError = msg.err
+		} else {
+			a.failuresView.SetGroups(msg.groups)
+			a.lastError = nil
+			cmds = append(cmds, a.fetchFailureChain())
+		}
+
+	case failureChainMsg:
+		if msg.gen != a.fetchGen {
+			break
+		}
+		if msg.err != nil {
+			a.lastError = msg.err
+		} else {
+			a.failuresView.SetChain(msg.jobID, db.RetryChain(msg.events))
+		}
+
+	case queuesLoadedMsg:
+		if msg.gen != a.fetchGen {
+			break
+		}
+		if msg.err != nil {
+			a.lastError = msg.err
+		} else {
+			a.queues = msg.queues
+		}
+
+	case goToJobMsg:
+		if msg.gen != a.fetchGen {
+			break
+		}
+		switch {
+		case errors.Is(msg.err, pgx.ErrNoRows):
+			cmds = append(cmds, a.showToast(fmt.Sprintf("Job #%d not found", msg.id), true))
+		case msg.err != nil:
+			a.lastError = msg.err
+		default:
+			if a.currentQueue != db.AllQueues && msg.job.QueueName != a.currentQueue {
+				cmds = append(cmds, a.switchQueue(msg.job.QueueName))
+			}
+			a.detailView.SetHighlights(nil)
 			a.detailView.SetJob(msg.job, msg.events)
 			a.showDetail = true
 			a.focus = focusDetail
 			a.sidebar.SetFocused(false)
 		}
 
+	case jobDetailMsg:
+		if msg.gen != a.fetchGen {
+			break
+		}
+		if msg.err != nil {
+			a.lastError = msg.err
+		} else {
+			a.detailView.SetHighlights(a.sidebar.Query().Highlights())
+			a.detailView.SetJob(msg.job, msg.events)
+			if !msg.refresh {
+				a.showDetail = true
+				a.focus = focusDetail
+				a.sidebar.SetFocused(false)
+			}
+		}
+
+	case fleetSummaryMsg:
+		a.fleetPolling[msg.conn] = false
+		if msg.client != nil {
+			if a.fleetClients[msg.conn] != nil || msg.conn == a.currentConn {
+				msg.client.Close() // a client for it was opened meanwhile
+			} else {
+				a.fleetClients[msg.conn] = msg.client
+			}
+		}
+		a.fleetView.SetSummary(msg.conn, msg.summary, msg.latency, msg.err)
+
+	case fleetTickMsg:
+		if a.fleetMode {
+			cmds = append(cmds, a.pollFleet(), a.fleetTickCmd())
+		} else {
+			a.fleetTicking = false
+		}
+
+	case jobActionMsg:
+		if msg.gen != a.fetchGen {
+			break
+		}
+		cmds = append(cmds, a.handleJobAction(msg)...)
+
+	case deferResultMsg:
+		if msg.gen != a.fetchGen {
+			break
+		}
+		cmds = append(cmds, a.handleDeferResult(msg)...)
+
+	case purgePreviewMsg:
+		if msg.gen != a.fetchGen {
+			break
+		}
+		cmds = append(cmds, a.confirmPurge(msg))
+
+	case purgeResultMsg:
+		cmds = append(cmds, a.handlePurgeResult(msg)...)
+
 	case notificationMsg:
-		cmds = append(cmds, a.listenCmd(), a.fetchJobs(), a.fetchActiveTabData())
+		if msg.listener != a.listener {
+			break // from a listener that has since been stopped; its reader ends here
+		}
+		cmds = append(cmds, a.listenCmd())
+		if msg.notification.JobID == 0 {
+			// Payload without a job id (Procrastinate 2.x): refresh everything
+			// once the coalescing window closes
+			a.pendingFullRefresh = true
+		} else {
+			if a.pendingNotified == nil {
+				a.pendingNotified = make(map[int64]bool)
+			}
+			a.pendingNotified[msg.notification.JobID] = true
+		}
+		if !a.flushScheduled {
+			a.flushScheduled = true
+			gen := a.fetchGen
+			cmds = append(cmds, tea.Tick(notifyCoalesceWindow, func(time.Time) tea.Msg {
+				return flushNotificationsMsg{gen: gen}
+			}))
+		}
+
+	case flushNotificationsMsg:
+		a.flushScheduled = false
+		ids := make([]int64, 0, len(a.pendingNotified))
+		for id := range a.pendingNotified {
+			ids = append(ids, id)
+		}
+		clear(a.pendingNotified)
+		fullRefresh := a.pendingFullRefresh
+		a.pendingFullRefresh = false
+		if msg.gen != a.fetchGen {
+			break
+		}
+		if fullRefresh {
+			cmds = append(cmds, a.fetchJobs(), a.fetchActiveTabData())
+		} else {
+			cmds = append(cmds, a.fetchNotifiedJobs(ids))
+		}
+
+	case notifiedJobsMsg:
+		if msg.gen != a.fetchGen {
+			break
+		}
+		if msg.err != nil {
+			a.lastError = msg.err
+			break
+		}
+		// The any-queue channel also reports other queues' jobs
+		var jobs []db.Job
+		for _, j := range msg.jobs {
+			if a.currentQueue == db.AllQueues || j.QueueName == a.currentQueue {
+				jobs = append(jobs, j)
+			}
+		}
+		a.liveView.AddJobs(jobs)
+		if cmd := a.sidebar.UpsertJobs(jobs); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case listenerStateMsg:
+		if msg.listener != a.listener {
+			break // from a listener that has since been stopped
+		}
+		prev := a.listenerState
+		a.listenerState = msg.state
+		cmds = append(cmds, a.listenerStateCmd())
+		if msg.state == db.ListenerConnected && prev != db.ListenerConnected {
+			// Notifications sent while disconnected are lost; catch up
+			cmds = append(cmds, a.fetchJobs(), a.fetchActiveTabData())
+		}
 
 	case tickMsg:
 		if a.connected {
@@ -270,16 +594,37 @@ func (a *App) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a.handleOverlayKey(msg)
 	}
 
-	// When the sidebar list is in filtering mode (user is typing a search),
-	// pass ALL keys to the sidebar so they go to the filter input.
-	// Only ctrl+c is allowed to quit.
-	if a.focus == focusSidebar && a.sidebar.IsFiltering() {
-		if msg.String() == "ctrl+c" {
-			return a, tea.Quit
+	if a.fleetMode {
+		return a.handleFleetKey(msg)
+	}
+
+	if a.focus == focusDetail && !a.showDetail && a.tabBar.Active() == TabOrphaned {
+		if handled, cmd := a.handleOrphanedKey(msg); handled {
+			return a, cmd
+		}
+	}
+	if a.focus == focusDetail && !a.showDetail && a.tabBar.Active() == TabStatus {
+		if handled, cmd := a.handleStatusKey(msg); handled {
+			return a, cmd
+		}
+	}
+	if a.focus == focusDetail && !a.showDetail && a.tabBar.Active() == TabWorkers {
+		if handled, cmd := a.handleWorkersKey(msg); handled {
+			return a, cmd
+		}
+	}
+	if a.focus == focusDetail && !a.showDetail && a.tabBar.Active() == TabFailures {
+		if handled, cmd := a.handleFailuresKey(msg); handled {
+			return a, cmd
 		}
-		var cmd tea.Cmd
-		a.sidebar, cmd = a.sidebar.Update(msg)
-		return a, cmd
+	}
+	if a.focus == focusDetail && !a.showDetail && a.tabBar.Active() == TabMetrics &&
+		key.Matches(msg, a.keys.Window) {
+		a.metricsView.CycleWindow()
+		if !a.connected {
+			return a, nil
+		}
+		return a, a.fetchMetrics()
 	}
 
 	switch {
@@ -290,6 +635,27 @@ func (a *App) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		a.overlay = overlayHelp
 		return a, nil
 
+	case key.Matches(msg, a.keys.Query):
+		return a, a.openQueryPrompt()
+
+	case key.Matches(msg, a.keys.GoTo):
+		if !a.connected {
+			return a, nil
+		}
+		return a, a.openPrompt(overlayGoTo, "# ", "job id", "")
+
+	case key.Matches(msg, a.keys.Defer):
+		if !a.connected {
+			return a, nil
+		}
+		return a, a.openDeferForm()
+
+	case key.Matches(msg, a.keys.Purge):
+		if a.connected {
+			a.openPurgePicker()
+		}
+		return a, nil
+
 	case key.Matches(msg, a.keys.FocusNext), key.Matches(msg, a.keys.FocusPrev):
 		a.toggleFocus()
 		return a, nil
@@ -338,6 +704,27 @@ func (a *App) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		a.openFilterPicker()
 		return a, nil
 
+	case key.Matches(msg, a.keys.Retry):
+		if a.connected {
+			return a, a.confirmRetry()
+		}
+		return a, nil
+
+	case key.Matches(msg, a.keys.Cancel):
+		if a.connected {
+			return a, a.confirmCancel()
+		}
+		return a, nil
+
+	case key.Matches(msg, a.keys.Abort):
+		if a.connected {
+			return a, a.confirmAbort()
+		}
+		return a, nil
+
+	case key.Matches(msg, a.keys.Fleet):
+		return a, a.openFleet()
+
 	case key.Matches(msg, a.keys.Dashboard):
 		if a.showDetail {
 			a.showDetail = false
@@ -358,6 +745,9 @@ func (a *App) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if a.focus == focusSidebar {
 		var cmd tea.Cmd
 		a.sidebar, cmd = a.sidebar.Update(msg)
+		if a.connected && a.sidebar.NeedsMore() {
+			cmd = tea.Batch(cmd, a.fetchMoreJobs())
+		}
 		return a, cmd
 	}
 
@@ -365,6 +755,147 @@ func (a *App) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, tea.Batch(cmds...)
 }
 
+// openFleet shows the fleet dashboard and starts polling every connection.
+func (a *App) openFleet() tea.Cmd {
+	a.fleetMode = true
+	cmds := []tea.Cmd{a.pollFleet()}
+	if !a.fleetTicking {
+		a.fleetTicking = true
+		cmds = append(cmds, a.fleetTickCmd())
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleFleetKey handles keys while the fleet dashboard is shown. Enter
+// drills into the highlighted connection.
+func (a *App) handleFleetKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, a.keys.Quit):
+		return a, tea.Quit
+	case key.Matches(msg, a.keys.Help):
+		a.overlay = overlayHelp
+	case key.Matches(msg, a.keys.Up):
+		a.fleetView.MoveCursor(-1)
+	case key.Matches(msg, a.keys.Down):
+		a.fleetView.MoveCursor(1)
+	case key.Matches(msg, a.keys.Back), key.Matches(msg, a.keys.Fleet):
+		a.fleetMode = false
+	case key.Matches(msg, a.keys.Enter):
+		a.fleetMode = false
+		if name := a.fleetView.Selected(); name != "" && name != a.currentConn {
+			return a, a.switchConnection(name)
+		}
+	default:
+		var cmd tea.Cmd
+		a.fleetView, cmd = a.fleetView.Update(msg)
+		return a, cmd
+	}
+	return a, nil
+}
+
+// handleStatusKey moves the task matrix cursor, sorts by the highlighted
+// column and, on enter, filters the sidebar to the highlighted task and
+// status. Returns false for keys it does not handle.
+func (a *App) handleStatusKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch {
+	case key.Matches(msg, a.keys.Up):
+		a.statusView.MoveCursor(-1, 0)
+	case key.Matches(msg, a.keys.Down):
+		a.statusView.MoveCursor(1, 0)
+	case key.Matches(msg, a.keys.Left):
+		a.statusView.MoveCursor(0, -1)
+	case key.Matches(msg, a.keys.Right):
+		a.statusView.MoveCursor(0, 1)
+	case key.Matches(msg, a.keys.Sort):
+		a.statusView.SortByCursor()
+	case key.Matches(msg, a.keys.Enter):
+		row, status := a.statusView.SelectedCell()
+		if row == "" || !a.connected {
+			return true, nil
+		}
+		if a.statusView.ByQueue() {
+			// Heatmap cell: open that queue with the cell's status selected
+			a.sidebar.SetTaskFilter("", status)
+			cmd := a.switchQueue(row)
+			a.focus = focusSidebar
+			a.sidebar.SetFocused(true)
+			return true, cmd
+		}
+		a.sidebar.SetTaskFilter(row, status)
+		cmd := a.sidebar.ResetPaging()
+		a.focus = focusSidebar
+		a.sidebar.SetFocused(true)
+		return true, tea.Batch(cmd, a.fetchJobs(), a.fetchActiveTabData())
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// handleWorkersKey moves the Workers tab cursor and, on enter, limits the
+// sidebar to the selected worker's doing jobs. Returns false for keys it
+// does not handle.
+func (a *App) handleWorkersKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch {
+	case key.Matches(msg, a.keys.Up):
+		a.workersView.MoveCursor(-1)
+	case key.Matches(msg, a.keys.Down):
+		a.workersView.MoveCursor(1)
+	case key.Matches(msg, a.keys.Enter):
+		worker := a.workersView.Selected()
+		if worker == nil || !a.connected {
+			return true, nil
+		}
+		id := worker.ID
+		a.sidebar.SetWorkerFilter(&id)
+		cmd := a.sidebar.ResetPaging()
+		a.focus = focusSidebar
+		a.sidebar.SetFocused(true)
+		return true, tea.Batch(cmd, a.fetchJobs(), a.fetchActiveTabData())
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// handleFailuresKey moves the Failures tab cursor across groups and, with
+// left and right, across the highlighted group's jobs, loading the selected
+// job's retry chain. On enter it shows the group's task's failed jobs, or
+// its jobs awaiting retry when none failed, in the sidebar. Returns false
+// for keys it does not handle.
+func (a *App) handleFailuresKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch {
+	case key.Matches(msg, a.keys.Up):
+		a.failuresView.MoveCursor(-1)
+		return true, a.fetchFailureChain()
+	case key.Matches(msg, a.keys.Down):
+		a.failuresView.MoveCursor(1)
+		return true, a.fetchFailureChain()
+	case key.Matches(msg, a.keys.Left):
+		a.failuresView.MoveJob(-1)
+		return true, a.fetchFailureChain()
+	case key.Matches(msg, a.keys.Right):
+		a.failuresView.MoveJob(1)
+		return true, a.fetchFailureChain()
+	case key.Matches(msg, a.keys.Enter):
+		group := a.failuresView.Selected()
+		if group == nil || !a.connected {
+			return true, nil
+		}
+		status := db.StatusFailed
+		if group.Failed == 0 {
+			status = db.StatusTodo
+		}
+		a.sidebar.SetTaskFilter(group.TaskName, string(status))
+		cmd := a.sidebar.ResetPaging()
+		a.focus = focusSidebar
+		a.sidebar.SetFocused(true)
+		return true, tea.Batch(cmd, a.fetchJobs(), a.fetchActiveTabData())
+	default:
+		return false, nil
+	}
+}
+
 func (a *App) handleOverlayKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch a.overlay {
 	case overlayHelp:
@@ -372,13 +903,109 @@ func (a *App) handleOverlayKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		a.overlay = overlayNone
 		return a, nil
 
-	case overlayQueuePicker, overlayConnPicker, overlayFilterPicker:
+	case overlayQueuePicker, overlayConnPicker, overlayFilterPicker, overlayOrphanAction, overlayPurgePicker:
 		return a.handlePickerKey(msg)
+
+	case overlayConfirm:
+		return a.handleConfirmKey(msg)
+
+	case overlayQuery:
+		return a.handleQueryKey(msg)
+
+	case overlayGoTo:
+		return a.handleGoToKey(msg)
+
+	case overlayDefer:
+		return a.handleDeferKey(msg)
+
+	case overlayPurge:
+		return a.handlePurgeKey(msg)
 	}
 
 	return a, nil
 }
 
+// newPromptInput creates the text input used by the prompts. The cursor
+// does not blink so the input needs no tick messages.
+func newPromptInput() textinput.Model {
+	ti := textinput.New()
+	ti.Cursor.SetMode(cursor.CursorStatic)
+	return ti
+}
+
+// openPrompt shows a text prompt overlay starting with value.
+func (a *App) openPrompt(overlay overlayMode, prompt, placeholder, value string) tea.Cmd {
+	a.overlay = overlay
+	a.promptErr = nil
+	a.promptInput.Prompt = prompt
+	a.promptInput.Placeholder = placeholder
+	a.promptInput.SetValue(value)
+	a.promptInput.CursorEnd()
+	return a.promptInput.Focus()
+}
+
+func (a *App) openQueryPrompt() tea.Cmd {
+	return a.openPrompt(overlayQuery, "/ ",
+		"status:failed task:send_* attempts>2 args.order_id=1003 scheduled<-1h", a.sidebar.Query().String())
+}
+
+// handleGoToKey reads a job ID and, on enter, loads that job.
+func (a *App) handleGoToKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		a.overlay = overlayNone
+		a.promptInput.Blur()
+		return a, nil
+
+	case tea.KeyEnter:
+		value := strings.TrimPrefix(strings.TrimSpace(a.promptInput.Value()), "#")
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			a.promptErr = fmt.Errorf("%q is not a job ID", value)
+			return a, nil
+		}
+		a.overlay = overlayNone
+		a.promptInput.Blur()
+		return a, a.goToJob(id)
+	}
+
+	var cmd tea.Cmd
+	a.promptInput, cmd = a.promptInput.Update(msg)
+	a.promptErr = nil
+	return a, cmd
+}
+
+// handleQueryKey edits the filter expression and, on enter, applies it to
+// the sidebar. An empty expression clears the query.
+func (a *App) handleQueryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		a.overlay = overlayNone
+		a.promptInput.Blur()
+		return a, nil
+
+	case tea.KeyEnter:
+		q, err := db.ParseQuery(a.promptInput.Value())
+		if err != nil {
+			a.promptErr = err
+			return a, nil
+		}
+		a.overlay = overlayNone
+		a.promptInput.Blur()
+		a.sidebar.SetQuery(q)
+		cmd := a.sidebar.ResetPaging()
+		if a.connected {
+			return a, tea.Batch(cmd, a.fetchJobs())
+		}
+		return a, cmd
+	}
+
+	var cmd tea.Cmd
+	a.promptInput, cmd = a.promptInput.Update(msg)
+	a.promptErr = nil
+	return a, cmd
+}
+
 func (a *App) handlePickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, a.keys.Back):
@@ -407,12 +1034,22 @@ func (a *App) handlePickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		currentOverlay := a.overlay
 		a.overlay = overlayNone
 
+		if currentOverlay == overlayOrphanAction {
+			return a, a.confirmOrphanAction(a.pickerIndex)
+		}
+
+		if currentOverlay == overlayPurgePicker {
+			a.purgeIncludeFailed = a.pickerIndex == 1
+			return a, a.openPrompt(overlayPurge, "older than ", "30d", "30d")
+		}
+
 		if currentOverlay == overlayFilterPicker {
-			a.sidebar.filterIndex = a.pickerIndex
+			a.sidebar.SetStatusFilter(a.pickerIndex)
+			cmd := a.sidebar.ResetPaging()
 			if a.connected {
-				return a, a.fetchJobs()
+				return a, tea.Batch(cmd, a.fetchJobs())
 			}
-			return a, nil
+			return a, cmd
 		}
 
 		if a.switchQueueFn != nil {
@@ -433,30 +1070,70 @@ func (a *App) handlePickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+func (a *App) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, a.keys.Confirm), key.Matches(msg, a.keys.Enter):
+		fn := a.confirmFn
+		a.overlay = overlayNone
+		a.confirmFn = nil
+		if fn != nil {
+			return a, fn()
+		}
+		return a, nil
+
+	case key.Matches(msg, a.keys.Back), msg.String() == "n", msg.String() == "N":
+		a.overlay = overlayNone
+		a.confirmFn = nil
+		return a, nil
+	}
+
+	return a, nil
+}
+
 func (a *App) openQueuePicker() {
 	if len(a.queues) == 0 {
 		return
 	}
 	a.overlay = overlayQueuePicker
-	a.pickerItems = a.queues
+	a.pickerItems = append([]string{allQueuesLabel}, a.queues...)
 	a.pickerIndex = 0
 	for i, q := range a.queues {
 		if q == a.currentQueue {
-			a.pickerIndex = i
+			a.pickerIndex = i + 1
 			break
 		}
 	}
 	a.switchQueueFn = func(queue string) tea.Cmd {
-		a.currentQueue = queue
-		a.fetchGen++
-		if a.listener != nil {
-			_ = a.listener.SwitchQueue(context.Background(), queue)
+		if queue == allQueuesLabel {
+			queue = db.AllQueues
 		}
-		if a.connected {
-			return tea.Batch(a.fetchJobs(), a.fetchActiveTabData(), a.fetchQueues())
-		}
-		return nil
+		return a.switchQueue(queue)
+	}
+}
+
+// switchQueue moves every view and the listener to another queue, or to
+// all queues for db.AllQueues.
+func (a *App) switchQueue(queue string) tea.Cmd {
+	a.currentQueue = queue
+	a.fetchGen++
+	a.sidebar.SetWorkerFilter(nil)
+	a.sidebar.SetShowQueue(queue == db.AllQueues)
+	resetCmd := a.sidebar.ResetPaging()
+	if a.listener != nil {
+		a.listener.SwitchQueue(queue)
+	}
+	if a.connected {
+		return tea.Batch(resetCmd, a.fetchJobs(), a.fetchActiveTabData(), a.fetchQueues())
+	}
+	return resetCmd
+}
+
+// queueLabel returns the display name of the current queue.
+func (a *App) queueLabel() string {
+	if a.currentQueue == db.AllQueues {
+		return allQueuesLabel
 	}
+	return a.currentQueue
 }
 
 func (a *App) openConnPicker() {
@@ -473,37 +1150,70 @@ func (a *App) openConnPicker() {
 			break
 		}
 	}
-	a.switchConnFn = func(connName string) tea.Cmd {
-		conn, err := a.config.GetConnection(connName)
-		if err != nil {
-			a.lastError = err
-			return nil
-		}
+	a.switchConnFn = a.switchConnection
+}
 
-		// Close old connection first
-		if a.dbClient != nil {
+// switchConnection tears down the current connection and connects to
+// another configured one at its default queue.
+func (a *App) switchConnection(connName string) tea.Cmd {
+	conn, err := a.config.GetConnection(connName)
+	if err != nil {
+		a.lastError = err
+		return nil
+	}
+
+	// Close old connection first, keeping its client for the fleet
+	// dashboard once it has polled this connection
+	if a.dbClient != nil {
+		if a.fleetView.Polled(a.currentConn) && a.fleetClients[a.currentConn] == nil {
+			a.fleetClients[a.currentConn] = a.dbClient
+		} else {
 			a.dbClient.Close()
-			a.dbClient = nil
-		}
-		if a.listener != nil {
-			a.listener.Stop()
-			a.listener = nil
 		}
+		a.dbClient = nil
+	}
+	if a.listener != nil {
+		a.listener.Stop()
+		a.listener = nil
+	}
 
-		a.currentConn = connName
-		a.currentQueue = conn.DefaultQueue
-		a.connected = false
-		a.lastError = nil
-		a.fetchGen++
-		a.sidebar.SetJobs(nil)
+	// Reuse the fleet dashboard's client for the new connection
+	client := a.fleetClients[connName]
+	delete(a.fleetClients, connName)
+
+	a.currentConn = connName
+	a.currentQueue = conn.DefaultQueue
+	a.sidebar.SetShowQueue(false)
+	a.applyConnectionMode(conn)
+	a.connected = false
+	a.lastError = nil
+	a.fetchGen++
+	a.sidebar.SetWorkerFilter(nil)
+	a.sidebar.ResetPaging()
+
+	return a.connectCmd(connName, conn.DefaultQueue, client)
+}
 
-		return a.connectCmd(connName, conn.DefaultQueue)
+// Close releases the database connections still held when the program
+// exits: the current client and listener and every fleet client.
+func (a *App) Close() {
+	if a.listener != nil {
+		a.listener.Stop()
+		a.listener = nil
+	}
+	if a.dbClient != nil {
+		a.dbClient.Close()
+		a.dbClient = nil
+	}
+	for name, c := range a.fleetClients {
+		c.Close()
+		delete(a.fleetClients, name)
 	}
 }
 
 func (a *App) openFilterPicker() {
 	a.overlay = overlayFilterPicker
-	a.pickerItems = filterLabels
+	a.pickerItems = a.sidebar.FilterLabels()
 	a.pickerIndex = a.sidebar.filterIndex
 }
 
@@ -529,6 +1239,30 @@ func (a *App) updateActiveView(msg tea.Msg) []tea.Cmd {
 		var cmd tea.Cmd
 		a.orphanedView, cmd = a.orphanedView.Update(msg)
 		cmds = append(cmds, cmd)
+	case TabWorkers:
+		var cmd tea.Cmd
+		a.workersView, cmd = a.workersView.Update(msg)
+		cmds = append(cmds, cmd)
+	case TabPeriodic:
+		var cmd tea.Cmd
+		a.periodicView, cmd = a.periodicView.Update(msg)
+		cmds = append(cmds, cmd)
+	case TabLocks:
+		var cmd tea.Cmd
+		a.locksView, cmd = a.locksView.Update(msg)
+		cmds = append(cmds, cmd)
+	case TabScheduled:
+		var cmd tea.Cmd
+		a.scheduledView, cmd = a.scheduledView.Update(msg)
+		cmds = append(cmds, cmd)
+	case TabMetrics:
+		var cmd tea.Cmd
+		a.metricsView, cmd = a.metricsView.Update(msg)
+		cmds = append(cmds, cmd)
+	case TabFailures:
+		var cmd tea.Cmd
+		a.failuresView, cmd = a.failuresView.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 	return cmds
 }
@@ -558,6 +1292,10 @@ func (a *App) View() string {
 	detailContent := a.renderDetail(detailWidth, contentHeight)
 
 	content := lipgloss.JoinHorizontal(lipgloss.Top, sidebarContent, detailContent)
+	if a.fleetMode {
+		content = SidebarFocusedStyle.Width(a.width).Height(contentHeight).Render(
+			padOrTruncate(a.fleetView.View(), a.width, contentHeight))
+	}
 	base := lipgloss.JoinVertical(lipgloss.Left, topBar, content, helpBar)
 
 	switch a.overlay {
@@ -569,6 +1307,27 @@ func (a *App) View() string {
 		return lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, a.renderPicker("Filter by Status", a.pickerItems, a.pickerIndex))
 	case overlayHelp:
 		return lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, a.renderHelpOverlay())
+	case overlayOrphanAction:
+		return lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, a.renderPicker("Orphaned Jobs", a.pickerItems, a.pickerIndex))
+	case overlayConfirm:
+		return lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, a.renderConfirm())
+	case overlayQuery:
+		return lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, a.renderPrompt("Query Jobs",
+			"fields: status task queue lock queueing_lock id attempts priority scheduled args.<key>\n"+
+				"a bare word matches the task name or args text",
+			"enter apply · empty clears · esc cancel"))
+	case overlayGoTo:
+		return lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, a.renderPrompt("Go to Job",
+			"opens the job in any queue of this connection",
+			"enter open · esc cancel"))
+	case overlayPurgePicker:
+		return lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, a.renderPicker("Purge Finished Jobs", a.pickerItems, a.pickerIndex))
+	case overlayPurge:
+		return lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, a.renderPrompt("Purge Finished Jobs",
+			"jobs whose latest event is older than this (e.g. 72h, 30d); counts are shown before deleting",
+			"enter preview · esc cancel"))
+	case overlayDefer:
+		return lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, a.deferForm.View(min(80, a.width-10)))
 	}
 
 	if a.toast != "" {
@@ -597,6 +1356,13 @@ func (a *App) recalcLayout() {
 	a.statusView.SetSize(tabContentWidth, tabContentHeight)
 	a.liveView.SetSize(tabContentWidth, tabContentHeight)
 	a.orphanedView.SetSize(tabContentWidth, tabContentHeight)
+	a.workersView.SetSize(tabContentWidth, tabContentHeight)
+	a.periodicView.SetSize(tabContentWidth, tabContentHeight)
+	a.locksView.SetSize(tabContentWidth, tabContentHeight)
+	a.scheduledView.SetSize(tabContentWidth, tabContentHeight)
+	a.metricsView.SetSize(tabContentWidth, tabContentHeight)
+	a.failuresView.SetSize(tabContentWidth, tabContentHeight)
+	a.fleetView.SetSize(a.width-2, contentHeight)
 	a.detailView.SetSize(tabContentWidth, tabContentHeight)
 }
 
@@ -2,23 +2,69 @@ package tui
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/matthewmyrick/procrastinate-cli/config"
 	"github.com/matthewmyrick/procrastinate-cli/db"
 )
 
+// fetchJobs refreshes the sidebar's first page. Pages loaded by scrolling
+// are kept, so the scroll position survives polling.
 func (a *App) fetchJobs() tea.Cmd {
 	if a.dbClient == nil {
 		return nil
 	}
-	pool := a.dbClient.Pool()
-	queue := a.currentQueue
+	client := a.dbClient
+	gen := a.fetchGen
+	filter := a.jobFilter()
+	listing := a.sidebar.Listing()
+	// Once the user has scrolled past the first page, keep the estimate from
+	// the first load rather than planning the count on every tick.
+	estimate := a.sidebar.Len() <= jobPageSize
+	return func() tea.Msg {
+		ctx := context.Background()
+		jobs, err := db.ListJobsPage(ctx, client, filter, nil, jobPageSize)
+		if err != nil {
+			return jobsLoadedMsg{err: err, listing: listing, gen: gen}
+		}
+		total := int64(-1)
+		if estimate {
+			total, err = db.EstimateJobs(ctx, client, filter)
+		}
+		return jobsLoadedMsg{jobs: jobs, limit: jobPageSize, total: total, err: err, listing: listing, gen: gen}
+	}
+}
+
+// fetchMoreJobs loads the next sidebar page after the last loaded job.
+func (a *App) fetchMoreJobs() tea.Cmd {
+	if a.dbClient == nil {
+		return nil
+	}
+	client := a.dbClient
 	gen := a.fetchGen
-	filter := a.sidebar.CurrentFilter()
+	filter := a.jobFilter()
+	listing := a.sidebar.Listing()
+	after := a.sidebar.StartLoadingMore()
 	return func() tea.Msg {
-		jobs, err := db.ListJobsFiltered(context.Background(), pool, queue, filter, 100, 0)
-		return jobsLoadedMsg{jobs: jobs, err: err, gen: gen}
+		jobs, err := db.ListJobsPage(context.Background(), client, filter, &after, jobPageSize)
+		return jobsLoadedMsg{jobs: jobs, limit: jobPageSize, appendPage: true, err: err, listing: listing, gen: gen}
+	}
+}
+
+// jobFilter returns the sidebar's current listing filter. A worker filter
+// spans all queues, since a worker may serve several.
+func (a *App) jobFilter() db.JobFilter {
+	if worker := a.sidebar.WorkerFilter(); worker != nil {
+		return db.JobFilter{Status: a.sidebar.CurrentFilter(), Worker: worker, Where: a.sidebar.Query()}
+	}
+	return db.JobFilter{
+		Queue:  a.currentQueue,
+		Status: a.sidebar.CurrentFilter(),
+		Task:   a.sidebar.TaskFilter(),
+		Where:  a.sidebar.Query(),
 	}
 }
 
@@ -26,12 +72,31 @@ func (a *App) fetchStatusCounts() tea.Cmd {
 	if a.dbClient == nil {
 		return nil
 	}
-	pool := a.dbClient.Pool()
+	client := a.dbClient
 	queue := a.currentQueue
 	gen := a.fetchGen
 	return func() tea.Msg {
-		counts, err := db.CountJobsByStatus(context.Background(), pool, queue)
-		return statusCountsMsg{counts: counts, err: err, gen: gen}
+		ctx := context.Background()
+		counts, err := db.CountJobsByStatus(ctx, client, queue)
+		if err != nil {
+			return statusCountsMsg{err: err, gen: gen}
+		}
+		trend, err := db.Backlog(ctx, client, queue)
+		if errors.Is(err, db.ErrNoEvents) {
+			trend, err = nil, nil
+		}
+		if err != nil {
+			return statusCountsMsg{err: err, gen: gen}
+		}
+		if queue == db.AllQueues {
+			queueCounts, err := db.CountJobsByQueueAndStatus(ctx, client)
+			if queueCounts == nil {
+				queueCounts = []db.QueueStatusCount{}
+			}
+			return statusCountsMsg{counts: counts, queueCounts: queueCounts, trend: trend, err: err, gen: gen}
+		}
+		taskCounts, err := db.CountJobsByTaskAndStatus(ctx, client, queue)
+		return statusCountsMsg{counts: counts, taskCounts: taskCounts, trend: trend, err: err, gen: gen}
 	}
 }
 
@@ -39,26 +104,129 @@ func (a *App) fetchOrphanedJobs() tea.Cmd {
 	if a.dbClient == nil {
 		return nil
 	}
-	pool := a.dbClient.Pool()
+	client := a.dbClient
 	queue := a.currentQueue
 	gen := a.fetchGen
 	threshold := a.config.OrphanThreshold
 	return func() tea.Msg {
-		jobs, err := db.ListOrphanedJobs(context.Background(), pool, queue, threshold)
+		jobs, err := db.ListOrphanedJobs(context.Background(), client, queue, threshold)
 		return orphanedJobsMsg{jobs: jobs, err: err, gen: gen}
 	}
 }
 
+func (a *App) fetchWorkers() tea.Cmd {
+	if a.dbClient == nil {
+		return nil
+	}
+	client := a.dbClient
+	gen := a.fetchGen
+	return func() tea.Msg {
+		workers, err := db.ListWorkers(context.Background(), client)
+		return workersLoadedMsg{workers: workers, err: err, gen: gen}
+	}
+}
+
+func (a *App) fetchPeriodicDefers() tea.Cmd {
+	if a.dbClient == nil {
+		return nil
+	}
+	client := a.dbClient
+	gen := a.fetchGen
+	return func() tea.Msg {
+		defers, err := db.ListPeriodicDefers(context.Background(), client)
+		return periodicDefersMsg{defers: defers, err: err, gen: gen}
+	}
+}
+
+func (a *App) fetchLocks() tea.Cmd {
+	if a.dbClient == nil {
+		return nil
+	}
+	client := a.dbClient
+	queue := a.currentQueue
+	gen := a.fetchGen
+	return func() tea.Msg {
+		ctx := context.Background()
+		groups, err := db.ListLockGroups(ctx, client, queue)
+		if err != nil {
+			return locksLoadedMsg{err: err, gen: gen}
+		}
+		queueingLocks, err := db.ListQueueingLocks(ctx, client, queue)
+		return locksLoadedMsg{groups: groups, queueingLocks: queueingLocks, err: err, gen: gen}
+	}
+}
+
+func (a *App) fetchScheduledJobs() tea.Cmd {
+	if a.dbClient == nil {
+		return nil
+	}
+	client := a.dbClient
+	queue := a.currentQueue
+	gen := a.fetchGen
+	return func() tea.Msg {
+		ctx := context.Background()
+		buckets, err := db.CountScheduledJobs(ctx, client, queue)
+		if err != nil {
+			return scheduledJobsMsg{err: err, gen: gen}
+		}
+		jobs, err := db.ListScheduledJobs(ctx, client, queue, scheduledJobLimit)
+		return scheduledJobsMsg{buckets: buckets, jobs: jobs, err: err, gen: gen}
+	}
+}
+
+func (a *App) fetchMetrics() tea.Cmd {
+	if a.dbClient == nil {
+		return nil
+	}
+	client := a.dbClient
+	queue := a.currentQueue
+	window := a.metricsView.Window()
+	request := a.metricsView.Request()
+	gen := a.fetchGen
+	return func() tea.Msg {
+		stats, err := db.TaskStats(context.Background(), client, queue, window)
+		return metricsLoadedMsg{stats: stats, err: err, request: request, gen: gen}
+	}
+}
+
+func (a *App) fetchFailureGroups() tea.Cmd {
+	if a.dbClient == nil {
+		return nil
+	}
+	client := a.dbClient
+	queue := a.currentQueue
+	gen := a.fetchGen
+	return func() tea.Msg {
+		groups, err := db.ListFailureGroups(context.Background(), client, queue)
+		return failureGroupsMsg{groups: groups, err: err, gen: gen}
+	}
+}
+
+// fetchFailureChain loads the events of the job selected in the
+// highlighted failure group, unless its retry chain is already shown.
+func (a *App) fetchFailureChain() tea.Cmd {
+	id := a.failuresView.SelectedJob()
+	if a.dbClient == nil || id == 0 || id == a.failuresView.ChainJobID() {
+		return nil
+	}
+	client := a.dbClient
+	gen := a.fetchGen
+	return func() tea.Msg {
+		events, err := db.GetJobEvents(context.Background(), client, id)
+		return failureChainMsg{jobID: id, events: events, err: err, gen: gen}
+	}
+}
+
 func (a *App) fetchRecentJobs() tea.Cmd {
 	if a.dbClient == nil {
 		return nil
 	}
-	pool := a.dbClient.Pool()
+	client := a.dbClient
 	queue := a.currentQueue
 	gen := a.fetchGen
 	return func() tea.Msg {
 		since := time.Now().Add(-1 * time.Hour)
-		jobs, err := db.ListRecentJobs(context.Background(), pool, queue, since)
+		jobs, err := db.ListRecentJobs(context.Background(), client, queue, since)
 		return recentJobsMsg{jobs: jobs, err: err, gen: gen}
 	}
 }
@@ -67,10 +235,10 @@ func (a *App) fetchQueues() tea.Cmd {
 	if a.dbClient == nil {
 		return nil
 	}
-	pool := a.dbClient.Pool()
+	client := a.dbClient
 	gen := a.fetchGen
 	return func() tea.Msg {
-		queues, err := db.ListQueues(context.Background(), pool)
+		queues, err := db.ListQueues(context.Background(), client)
 		return queuesLoadedMsg{queues: queues, err: err, gen: gen}
 	}
 }
@@ -79,18 +247,62 @@ func (a *App) fetchJobDetail(id int64) tea.Cmd {
 	if a.dbClient == nil {
 		return nil
 	}
-	pool := a.dbClient.Pool()
+	client := a.dbClient
 	gen := a.fetchGen
 	return func() tea.Msg {
-		job, err := db.GetJob(context.Background(), pool, id)
+		job, err := db.GetJob(context.Background(), client, id)
 		if err != nil {
 			return jobDetailMsg{err: err, gen: gen}
 		}
-		events, err := db.GetJobEvents(context.Background(), pool, id)
+		events, err := db.GetJobEvents(context.Background(), client, id)
 		return jobDetailMsg{job: job, events: events, err: err, gen: gen}
 	}
 }
 
+// goToJob loads a job by ID regardless of the current queue and filters.
+func (a *App) goToJob(id int64) tea.Cmd {
+	if a.dbClient == nil {
+		return nil
+	}
+	client := a.dbClient
+	gen := a.fetchGen
+	return func() tea.Msg {
+		ctx := context.Background()
+		job, err := db.GetJob(ctx, client, id)
+		if err != nil {
+			return goToJobMsg{id: id, err: err, gen: gen}
+		}
+		events, err := db.GetJobEvents(ctx, client, id)
+		return goToJobMsg{id: id, job: job, events: events, err: err, gen: gen}
+	}
+}
+
+// refreshJobDetail re-fetches the open job without moving focus.
+func (a *App) refreshJobDetail(id int64) tea.Cmd {
+	fetch := a.fetchJobDetail(id)
+	if fetch == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := fetch().(jobDetailMsg)
+		msg.refresh = true
+		return msg
+	}
+}
+
+// fetchNotifiedJobs loads just the jobs named in a batch of notifications.
+func (a *App) fetchNotifiedJobs(ids []int64) tea.Cmd {
+	if a.dbClient == nil || len(ids) == 0 {
+		return nil
+	}
+	client := a.dbClient
+	gen := a.fetchGen
+	return func() tea.Msg {
+		jobs, err := db.GetJobs(context.Background(), client, ids)
+		return notifiedJobsMsg{jobs: jobs, err: err, gen: gen}
+	}
+}
+
 func (a *App) fetchActiveTabData() tea.Cmd {
 	switch a.tabBar.Active() {
 	case TabStatus:
@@ -99,10 +311,70 @@ func (a *App) fetchActiveTabData() tea.Cmd {
 		return a.fetchRecentJobs()
 	case TabOrphaned:
 		return a.fetchOrphanedJobs()
+	case TabWorkers:
+		return a.fetchWorkers()
+	case TabPeriodic:
+		return a.fetchPeriodicDefers()
+	case TabLocks:
+		return a.fetchLocks()
+	case TabScheduled:
+		return a.fetchScheduledJobs()
+	case TabMetrics:
+		return a.fetchMetrics()
+	case TabFailures:
+		return a.fetchFailureGroups()
 	}
 	return nil
 }
 
+// pollFleet summarizes every configured connection that has no poll in
+// flight, through the current connection's client or a cached fleet client,
+// opening one for connections not yet reached. Each poll, connecting
+// included, is bounded by fleetPollTimeout so an unreachable host cannot
+// hold up its row.
+func (a *App) pollFleet() tea.Cmd {
+	var cmds []tea.Cmd
+	for i := range a.config.Connections {
+		conn := &a.config.Connections[i]
+		if a.fleetPolling[conn.Name] {
+			continue
+		}
+		a.fleetPolling[conn.Name] = true
+
+		client := a.fleetClients[conn.Name]
+		if conn.Name == a.currentConn && a.dbClient != nil {
+			client = a.dbClient
+		}
+		threshold := a.config.OrphanThreshold
+		cmds = append(cmds, func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), fleetPollTimeout)
+			defer cancel()
+
+			msg := fleetSummaryMsg{conn: conn.Name}
+			if client == nil {
+				connStr := fmt.Sprintf("%s&connect_timeout=%d", config.ConnString(conn), int(fleetConnectTimeout.Seconds()))
+				c, err := db.NewClient(ctx, connStr)
+				if err != nil {
+					msg.err = err
+					return msg
+				}
+				client, msg.client = c, c
+			}
+			start := time.Now()
+			msg.summary, msg.err = db.Summarize(ctx, client, threshold)
+			msg.latency = time.Since(start)
+			return msg
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+func (a *App) fleetTickCmd() tea.Cmd {
+	return tea.Tick(a.config.PollInterval, func(t time.Time) tea.Msg {
+		return fleetTickMsg(t)
+	})
+}
+
 func (a *App) tickCmd() tea.Cmd {
 	interval := a.config.PollInterval
 	return tea.Tick(interval, func(t time.Time) tea.Msg {
@@ -114,12 +386,28 @@ func (a *App) listenCmd() tea.Cmd {
 	if a.listener == nil {
 		return nil
 	}
-	ch := a.listener.Notifications()
+	listener := a.listener
+	ch := listener.Notifications()
 	return func() tea.Msg {
 		notif, ok := <-ch
 		if !ok {
 			return nil
 		}
-		return notificationMsg{notification: notif}
+		return notificationMsg{listener: listener, notification: notif}
+	}
+}
+
+func (a *App) listenerStateCmd() tea.Cmd {
+	if a.listener == nil {
+		return nil
+	}
+	listener := a.listener
+	ch := listener.States()
+	return func() tea.Msg {
+		state, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return listenerStateMsg{listener: listener, state: state}
 	}
 }
@@ -0,0 +1,189 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+// Defer form fields, in tab order.
+const (
+	deferFieldTask = iota
+	deferFieldQueue
+	deferFieldArgs
+	deferFieldPriority
+	deferFieldLock
+	deferFieldQueueingLock
+	deferFieldSchedule
+	deferFieldCount
+)
+
+var deferFieldLabels = [deferFieldCount]string{
+	"Task", "Queue", "Args", "Priority", "Lock", "Queueing lock", "Schedule",
+}
+
+var deferFieldPlaceholders = [deferFieldCount]string{
+	"task name",
+	"queue",
+	`{"key": "value"}`,
+	"0",
+	"none",
+	"none",
+	"now, a delay like 10m or a time like 2006-01-02T15:04",
+}
+
+// DeferForm collects the fields of a job to enqueue.
+type DeferForm struct {
+	inputs [deferFieldCount]textinput.Model
+	focus  int
+	err    error // why the last submit was rejected
+}
+
+// NewDeferForm creates an empty defer form.
+func NewDeferForm() DeferForm {
+	var f DeferForm
+	for i := range f.inputs {
+		ti := textinput.New()
+		ti.Cursor.SetMode(cursor.CursorStatic)
+		ti.Prompt = ""
+		ti.Placeholder = deferFieldPlaceholders[i]
+		f.inputs[i] = ti
+	}
+	return f
+}
+
+// Open clears the form, fills in queue and focuses the task field.
+func (f *DeferForm) Open(queue string) tea.Cmd {
+	for i := range f.inputs {
+		f.inputs[i].SetValue("")
+		f.inputs[i].Blur()
+	}
+	f.inputs[deferFieldQueue].SetValue(queue)
+	f.inputs[deferFieldArgs].SetValue("{}")
+	f.err = nil
+	f.focus = deferFieldTask
+	return f.inputs[f.focus].Focus()
+}
+
+// Close blurs the focused field.
+func (f *DeferForm) Close() {
+	f.inputs[f.focus].Blur()
+}
+
+// MoveFocus moves to the next (delta 1) or previous (delta -1) field.
+func (f *DeferForm) MoveFocus(delta int) tea.Cmd {
+	f.inputs[f.focus].Blur()
+	f.focus = (f.focus + delta + deferFieldCount) % deferFieldCount
+	f.inputs[f.focus].CursorEnd()
+	return f.inputs[f.focus].Focus()
+}
+
+// SetError shows why the form could not be submitted.
+func (f *DeferForm) SetError(err error) {
+	f.err = err
+}
+
+// Update passes a key to the focused field.
+func (f *DeferForm) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+	f.err = nil
+	return cmd
+}
+
+// Request validates the form and builds the defer request.
+func (f *DeferForm) Request(now time.Time) (db.DeferRequest, error) {
+	value := func(field int) string {
+		return strings.TrimSpace(f.inputs[field].Value())
+	}
+	optional := func(field int) *string {
+		if v := value(field); v != "" {
+			return &v
+		}
+		return nil
+	}
+
+	req := db.DeferRequest{
+		TaskName:     value(deferFieldTask),
+		Queue:        value(deferFieldQueue),
+		Lock:         optional(deferFieldLock),
+		QueueingLock: optional(deferFieldQueueingLock),
+	}
+	if req.TaskName == "" {
+		return req, fmt.Errorf("task name is required")
+	}
+	if req.Queue == "" {
+		return req, fmt.Errorf("queue is required")
+	}
+
+	args := value(deferFieldArgs)
+	if args == "" {
+		args = "{}"
+	}
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(args), &obj); err != nil || obj == nil {
+		return req, fmt.Errorf("args must be a JSON object")
+	}
+	req.Args = json.RawMessage(args)
+
+	if p := value(deferFieldPriority); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return req, fmt.Errorf("priority must be a whole number")
+		}
+		req.Priority = n
+	}
+
+	at, err := db.ParseSchedule(value(deferFieldSchedule), now)
+	if err != nil {
+		return req, err
+	}
+	req.ScheduledAt = at
+	return req, nil
+}
+
+// View renders the form.
+func (f *DeferForm) View(width int) string {
+	var b strings.Builder
+
+	b.WriteString(TitleStyle.Render("Defer Job"))
+	b.WriteString("\n\n")
+
+	labelWidth := 0
+	for _, l := range deferFieldLabels {
+		labelWidth = max(labelWidth, len(l))
+	}
+	for i, in := range f.inputs {
+		in.Width = max(width-labelWidth-8, 10)
+		style := lipgloss.NewStyle().Foreground(ColorMuted)
+		marker := "  "
+		if i == f.focus {
+			style = lipgloss.NewStyle().Foreground(ColorWhite).Bold(true)
+			marker = "► "
+		}
+		b.WriteString(style.Render(fmt.Sprintf("%s%-*s", marker, labelWidth, deferFieldLabels[i])))
+		b.WriteString("  ")
+		b.WriteString(in.View())
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+	if f.err != nil {
+		b.WriteString(lipgloss.NewStyle().Foreground(ColorError).Render(f.err.Error()))
+		b.WriteString("\n\n")
+	}
+	b.WriteString(muted.Render("enqueued through Procrastinate's defer function"))
+	b.WriteString("\n")
+	b.WriteString(muted.Render("tab/shift+tab move · enter defer · esc cancel"))
+
+	return OverlayStyle.Width(width).Render(b.String())
+}
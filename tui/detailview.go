@@ -13,12 +13,13 @@ import (
 
 // DetailView shows full details for a selected job.
 type DetailView struct {
-	job      *db.Job
-	events   []db.JobEvent
-	viewport viewport.Model
-	visible  bool
-	width    int
-	height   int
+	job        *db.Job
+	events     []db.JobEvent
+	highlights []string // text to highlight in the args
+	viewport   viewport.Model
+	visible    bool
+	width      int
+	height     int
 }
 
 // NewDetailView creates a new detail view.
@@ -35,6 +36,15 @@ func (d *DetailView) SetJob(job *db.Job, events []db.JobEvent) {
 	d.viewport.GotoTop()
 }
 
+// SetHighlights sets the text highlighted in the args block, typically the
+// values the sidebar query searched args for.
+func (d *DetailView) SetHighlights(terms []string) {
+	d.highlights = terms
+	if d.job != nil {
+		d.viewport.SetContent(d.renderContent())
+	}
+}
+
 // SetVisible controls whether the view is active.
 func (d *DetailView) SetVisible(v bool) {
 	d.visible = v
@@ -122,7 +132,7 @@ func (d *DetailView) renderContent() string {
 	if err := json.Unmarshal(j.Args, &prettyArgs); err == nil {
 		pretty, err := json.MarshalIndent(prettyArgs, "  ", "  ")
 		if err == nil {
-			b.WriteString(lipgloss.NewStyle().Foreground(ColorSecondary).Render("  " + string(pretty)))
+			b.WriteString(d.highlightArgs("  " + string(pretty)))
 		} else {
 			b.WriteString("  " + string(j.Args))
 		}
@@ -138,18 +148,89 @@ func (d *DetailView) renderContent() string {
 		b.WriteString(eventsLabel)
 		b.WriteString("\n")
 
-		for _, e := range d.events {
+		for i, e := range d.events {
 			ts := lipgloss.NewStyle().Foreground(ColorMuted).
 				Render(e.At.Local().Format("2006-01-02 15:04:05"))
 			eventType := lipgloss.NewStyle().Foreground(ColorWhite).Bold(true).
 				Render(fmt.Sprintf("%-22s", e.Type))
-			b.WriteString(fmt.Sprintf("  %s  %s\n", eventType, ts))
+			gap := ""
+			if i > 0 {
+				gap = lipgloss.NewStyle().Foreground(ColorMuted).
+					Render("+" + formatDuration(e.At.Sub(d.events[i-1].At)))
+			}
+			b.WriteString(fmt.Sprintf("  %s  %s  %s\n", eventType, ts, gap))
+		}
+
+		if chain := db.RetryChain(d.events); len(chain) > 1 {
+			b.WriteString("\n")
+			b.WriteString(LabelStyle.Render("Retry Chain:"))
+			b.WriteString("\n")
+			renderRetryChain(&b, chain)
 		}
 	}
 
 	return b.String()
 }
 
+// highlightArgs renders the args text, marking every case-insensitive
+// occurrence of the highlight terms. Lines are styled one at a time so
+// lipgloss does not pad them to a common width.
+func (d *DetailView) highlightArgs(text string) string {
+	plain := lipgloss.NewStyle().Foreground(ColorSecondary)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		var out strings.Builder
+		pos := 0
+		for _, span := range highlightSpans(line, d.highlights) {
+			if span[0] > pos {
+				out.WriteString(plain.Render(line[pos:span[0]]))
+			}
+			out.WriteString(HighlightStyle.Render(line[span[0]:span[1]]))
+			pos = span[1]
+		}
+		if pos < len(line) {
+			out.WriteString(plain.Render(line[pos:]))
+		}
+		lines[i] = out.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// highlightSpans returns the byte ranges of line matching any of the terms,
+// ignoring case, in order and without overlaps. At a position where several
+// terms match, the longest wins. Lines whose case folding changes byte
+// offsets are not highlighted.
+func highlightSpans(line string, terms []string) [][2]int {
+	lower := strings.ToLower(line)
+	if len(lower) != len(line) {
+		return nil
+	}
+	var spans [][2]int
+	for pos := 0; pos < len(line); {
+		start, end := len(line), len(line)
+		for _, term := range terms {
+			term = strings.ToLower(term)
+			if term == "" {
+				continue
+			}
+			k := strings.Index(lower[pos:], term)
+			if k < 0 {
+				continue
+			}
+			if pos+k < start || (pos+k == start && pos+k+len(term) > end) {
+				start, end = pos+k, pos+k+len(term)
+			}
+		}
+		if start == len(line) {
+			break
+		}
+		spans = append(spans, [2]int{start, end})
+		pos = end
+	}
+	return spans
+}
+
 func (d *DetailView) writeField(b *strings.Builder, label, value string) {
 	l := LabelStyle.Render(label + ":")
 	v := ValueStyle.Render(value)
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHighlightSpans(t *testing.T) {
+	tests := []struct {
+		line  string
+		terms []string
+		want  [][2]int
+	}{
+		{`"order_id": 1003`, nil, nil},
+		{`"order_id": 1003`, []string{"1003"}, [][2]int{{12, 16}}},
+		{`"name": "Alice"`, []string{"alice"}, [][2]int{{9, 14}}},
+		{`"a": 1, "b": 1`, []string{"1"}, [][2]int{{5, 6}, {13, 14}}},
+		{`"order:1003"`, []string{"order:1003"}, [][2]int{{1, 11}}},
+		{"abcdef", []string{"cd", "bcde"}, [][2]int{{1, 5}}},
+		{"abcdef", []string{"ab", "abc"}, [][2]int{{0, 3}}},
+		{"abcabc", []string{"", "c"}, [][2]int{{2, 3}, {5, 6}}},
+		{"İstanbul", []string{"stan"}, nil}, // case folding changes the length
+	}
+	for _, tt := range tests {
+		got := highlightSpans(tt.line, tt.terms)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("highlightSpans(%q, %q) = %v, want %v", tt.line, tt.terms, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+// failuresHeaderLines is the number of lines rendered above the first group row.
+const failuresHeaderLines = 4
+
+// FailuresView groups failed and retrying jobs by task and attempts, and
+// shows the retry chain of one of the highlighted group's jobs.
+type FailuresView struct {
+	groups     []db.FailureGroup
+	cursor     int
+	job        int   // index into the highlighted group's JobIDs
+	chainJobID int64 // job the chain belongs to; 0 when none is loaded
+	chain      []db.Attempt
+	viewport   viewport.Model
+	width      int
+	height     int
+}
+
+// NewFailuresView creates a new failure analysis view.
+func NewFailuresView() FailuresView {
+	return FailuresView{}
+}
+
+// SetGroups updates the failure groups.
+func (f *FailuresView) SetGroups(groups []db.FailureGroup) {
+	f.groups = groups
+	if f.cursor >= len(groups) {
+		f.cursor = max(len(groups)-1, 0)
+	}
+	// Stay on the same job when it is still in the group.
+	job := 0
+	if g := f.Selected(); g != nil {
+		job = max(slices.Index(g.JobIDs, f.chainJobID), 0)
+	}
+	f.job = job
+	f.refresh()
+}
+
+// SetChain sets the retry chain shown for a job.
+func (f *FailuresView) SetChain(jobID int64, chain []db.Attempt) {
+	f.chainJobID = jobID
+	f.chain = chain
+	f.refresh()
+}
+
+// ChainJobID returns the job whose retry chain is loaded, or 0.
+func (f *FailuresView) ChainJobID() int64 {
+	return f.chainJobID
+}
+
+// MoveCursor moves the highlighted row by delta, clamped to the list.
+func (f *FailuresView) MoveCursor(delta int) {
+	f.cursor = min(max(f.cursor+delta, 0), max(len(f.groups)-1, 0))
+	f.job = 0
+	f.refresh()
+}
+
+// MoveJob steps through the highlighted group's jobs by delta, clamped to
+// the group.
+func (f *FailuresView) MoveJob(delta int) {
+	g := f.Selected()
+	if g == nil {
+		return
+	}
+	f.job = min(max(f.job+delta, 0), max(len(g.JobIDs)-1, 0))
+	f.refresh()
+}
+
+// SelectedJob returns the job whose retry chain should be shown, or 0 if
+// the highlighted group has none.
+func (f *FailuresView) SelectedJob() int64 {
+	g := f.Selected()
+	if g == nil || f.job >= len(g.JobIDs) {
+		return 0
+	}
+	return g.JobIDs[f.job]
+}
+
+// Selected returns the highlighted group, or nil if there are none.
+func (f *FailuresView) Selected() *db.FailureGroup {
+	if f.cursor >= len(f.groups) {
+		return nil
+	}
+	return &f.groups[f.cursor]
+}
+
+// SetSize updates the viewport dimensions.
+func (f *FailuresView) SetSize(width, height int) {
+	f.width = width
+	f.height = height
+	f.viewport.Width = width
+	f.viewport.Height = height
+	f.refresh()
+}
+
+// Update handles messages for the failures view.
+func (f FailuresView) Update(msg tea.Msg) (FailuresView, tea.Cmd) {
+	var cmd tea.Cmd
+	f.viewport, cmd = f.viewport.Update(msg)
+	return f, cmd
+}
+
+// View renders the failures view.
+func (f FailuresView) View() string {
+	return f.viewport.View()
+}
+
+// refresh re-renders the content and scrolls to keep the cursor visible.
+func (f *FailuresView) refresh() {
+	f.viewport.SetContent(f.renderContent())
+	if len(f.groups) == 0 {
+		return
+	}
+	line := failuresHeaderLines + f.cursor
+	if line < f.viewport.YOffset {
+		f.viewport.SetYOffset(line)
+	} else if line >= f.viewport.YOffset+f.viewport.Height {
+		f.viewport.SetYOffset(line - f.viewport.Height + 1)
+	}
+}
+
+// taskFailures totals a task's groups to judge whether it is flaky.
+type taskFailures struct {
+	failed, pending, recovered int64
+	retried                    bool // some failed or pending job had more than one attempt
+}
+
+func (f *FailuresView) renderContent() string {
+	if len(f.groups) == 0 {
+		return lipgloss.NewStyle().
+			Foreground(ColorMuted).
+			Padding(1, 1).
+			Render("No failed or retrying jobs")
+	}
+
+	tasks := make(map[string]*taskFailures)
+	var failed, pending int64
+	for _, g := range f.groups {
+		t := tasks[g.TaskName]
+		if t == nil {
+			t = &taskFailures{}
+			tasks[g.TaskName] = t
+		}
+		t.failed += g.Failed
+		t.pending += g.Pending
+		t.recovered += g.Recovered
+		t.retried = t.retried || (g.Failed > 0 && g.Attempts > 1) || g.Pending > 0
+		failed += g.Failed
+		pending += g.Pending
+	}
+
+	var b strings.Builder
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorWhite)
+
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  %d failed job(s), %d awaiting retry, across %d task(s)",
+		failed, pending, len(tasks))))
+	b.WriteString("\n\n")
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  %-24s %8s %8s %8s %10s   %s",
+		"Task", "Attempts", "Failed", "Pending", "Recovered", "Verdict")))
+	b.WriteString("\n")
+	b.WriteString(muted.Render("  " + strings.Repeat("─", f.width-4)))
+	b.WriteString("\n")
+
+	for i, g := range f.groups {
+		cursor := "  "
+		if i == f.cursor {
+			cursor = lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render("► ")
+		}
+
+		task, verdict := "", ""
+		if i == 0 || f.groups[i-1].TaskName != g.TaskName {
+			task = g.TaskName
+			verdict = renderVerdict(tasks[g.TaskName])
+		}
+
+		failedStr := muted.Render(fmt.Sprintf("%8d", g.Failed))
+		if g.Failed > 0 {
+			failedStr = StatusStyle(string(db.StatusFailed)).Render(fmt.Sprintf("%8d", g.Failed))
+		}
+		pendingStr := muted.Render(fmt.Sprintf("%8d", g.Pending))
+		if g.Pending > 0 {
+			pendingStr = lipgloss.NewStyle().Foreground(ColorWarning).Render(fmt.Sprintf("%8d", g.Pending))
+		}
+		recovered := muted.Render(fmt.Sprintf("%10d", g.Recovered))
+		if g.Recovered > 0 {
+			recovered = StatusStyle(string(db.StatusSucceeded)).Render(fmt.Sprintf("%10d", g.Recovered))
+		}
+
+		b.WriteString(fmt.Sprintf("%s%-24s %8d %s %s %s   %s\n",
+			cursor, truncate(task, 24), g.Attempts, failedStr, pendingStr, recovered, verdict))
+	}
+
+	// Retry chain of the selected job in the highlighted group
+	b.WriteString("\n")
+	if g := f.Selected(); g != nil && len(g.JobIDs) > 0 && f.SelectedJob() == f.chainJobID {
+		b.WriteString(headerStyle.Render(fmt.Sprintf("  Retry chain of job #%d", f.chainJobID)))
+		b.WriteString(muted.Render(fmt.Sprintf("  (%d of %d)", f.job+1, len(g.JobIDs))))
+		b.WriteString("\n")
+		renderRetryChain(&b, f.chain)
+	} else if g != nil && len(g.JobIDs) == 0 {
+		b.WriteString(muted.Render(fmt.Sprintf("  No failed or pending jobs with %d attempt(s)", g.Attempts)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(muted.Render("  h/l: previous/next job in the group · enter: show the task's jobs in the sidebar"))
+
+	return b.String()
+}
+
+// renderVerdict labels a task as flaky when some of its jobs succeeded
+// after retrying, retrying while its only retried jobs are still pending,
+// and broken when retried jobs only ever failed.
+func renderVerdict(t *taskFailures) string {
+	switch {
+	case t.recovered > 0:
+		pct := float64(t.recovered) / float64(t.recovered+t.failed) * 100
+		return lipgloss.NewStyle().Foreground(ColorWarning).Bold(true).Render(
+			fmt.Sprintf("flaky (%.0f%% recover)", pct))
+	case t.failed == 0 && t.pending > 0:
+		return lipgloss.NewStyle().Foreground(ColorWarning).Render("retrying")
+	case t.retried:
+		return lipgloss.NewStyle().Foreground(ColorError).Bold(true).Render("broken")
+	default:
+		return lipgloss.NewStyle().Foreground(ColorMuted).Render("no retries")
+	}
+}
+
+// renderRetryChain writes one line per attempt with when it started, the
+// time since the previous attempt ended, its run time and outcome.
+func renderRetryChain(b *strings.Builder, chain []db.Attempt) {
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+	if len(chain) == 0 {
+		b.WriteString(muted.Render("  No events recorded"))
+		b.WriteString("\n")
+		return
+	}
+
+	b.WriteString(muted.Render(fmt.Sprintf("  %-4s %-19s  %-10s %-10s %s", "#", "Started", "After", "Run", "Outcome")))
+	b.WriteString("\n")
+
+	now := time.Now()
+	var prevEnd *time.Time
+	for i, a := range chain {
+		started, after, run := "-", "-", "-"
+		if a.StartedAt != nil {
+			started = a.StartedAt.Local().Format("2006-01-02 15:04:05")
+			if prevEnd != nil {
+				after = "+" + formatLatency(a.StartedAt.Sub(*prevEnd))
+			}
+			if a.EndedAt != nil {
+				run = formatLatency(a.EndedAt.Sub(*a.StartedAt))
+			}
+		}
+
+		var outcome string
+		switch {
+		case a.Pending():
+			started = "pending"
+			if a.QueuedAt != nil {
+				after = "queued " + formatDuration(now.Sub(*a.QueuedAt)) + " ago"
+			}
+			outcome = lipgloss.NewStyle().Foreground(ColorWarning).Bold(true).Render("retry pending")
+		case a.Outcome == "":
+			outcome = StatusStyle(string(db.StatusDoing)).Render("running")
+		case a.Outcome == "deferred_for_retry" || a.Outcome == "retried":
+			outcome = StatusStyle(string(db.StatusFailed)).Render("failed, retried")
+		default:
+			outcome = StatusStyle(a.Outcome).Render(a.Outcome)
+		}
+
+		b.WriteString(fmt.Sprintf("  %-4d %-19s  %-10s %-10s %s\n", i+1, started, after, run, outcome))
+		if a.EndedAt != nil {
+			prevEnd = a.EndedAt
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/matthewmyrick/procrastinate-cli/config"
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+// fleetHeaderLines is the number of lines rendered above the first row.
+const fleetHeaderLines = 4
+
+// fleetRow is the latest summary for one configured connection.
+type fleetRow struct {
+	name        string
+	environment string
+	summary     *db.Summary
+	latency     time.Duration
+	err         error
+	polled      bool // at least one poll has finished
+}
+
+// FleetView shows one summary row per configured connection.
+type FleetView struct {
+	rows     []fleetRow
+	cursor   int
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+// NewFleetView creates a fleet view with a row per connection, in config
+// order.
+func NewFleetView(conns []config.Connection) FleetView {
+	rows := make([]fleetRow, len(conns))
+	for i, c := range conns {
+		rows[i] = fleetRow{name: c.Name, environment: c.Environment}
+	}
+	return FleetView{rows: rows}
+}
+
+// SetSummary records the outcome of polling one connection.
+func (f *FleetView) SetSummary(name string, summary *db.Summary, latency time.Duration, err error) {
+	for i := range f.rows {
+		if f.rows[i].name == name {
+			f.rows[i].summary = summary
+			f.rows[i].latency = latency
+			f.rows[i].err = err
+			f.rows[i].polled = true
+		}
+	}
+	f.refresh()
+}
+
+// Polled reports whether a poll of the connection has finished.
+func (f *FleetView) Polled(name string) bool {
+	for _, r := range f.rows {
+		if r.name == name {
+			return r.polled
+		}
+	}
+	return false
+}
+
+// MoveCursor moves the highlighted row by delta, clamped to the list.
+func (f *FleetView) MoveCursor(delta int) {
+	f.cursor = min(max(f.cursor+delta, 0), max(len(f.rows)-1, 0))
+	f.refresh()
+}
+
+// Selected returns the highlighted connection name.
+func (f *FleetView) Selected() string {
+	if f.cursor >= len(f.rows) {
+		return ""
+	}
+	return f.rows[f.cursor].name
+}
+
+// SetSize updates the viewport dimensions.
+func (f *FleetView) SetSize(width, height int) {
+	f.width = width
+	f.height = height
+	f.viewport.Width = width
+	f.viewport.Height = height
+	f.refresh()
+}
+
+// Update handles messages for the fleet view.
+func (f FleetView) Update(msg tea.Msg) (FleetView, tea.Cmd) {
+	var cmd tea.Cmd
+	f.viewport, cmd = f.viewport.Update(msg)
+	return f, cmd
+}
+
+// View renders the fleet view.
+func (f FleetView) View() string {
+	return f.viewport.View()
+}
+
+// refresh re-renders the content and scrolls to keep the cursor visible.
+func (f *FleetView) refresh() {
+	f.viewport.SetContent(f.renderContent())
+	line := fleetHeaderLines + f.cursor
+	if line < f.viewport.YOffset {
+		f.viewport.SetYOffset(line)
+	} else if line >= f.viewport.YOffset+f.viewport.Height {
+		f.viewport.SetYOffset(line - f.viewport.Height + 1)
+	}
+}
+
+func (f *FleetView) renderContent() string {
+	var b strings.Builder
+
+	up, down := 0, 0
+	for _, r := range f.rows {
+		switch {
+		case r.err != nil:
+			down++
+		case r.summary != nil:
+			up++
+		}
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorWhite)
+	b.WriteString(titleStyle.Render(fmt.Sprintf("  Fleet: %d connection(s), %d up", len(f.rows), up)))
+	if down > 0 {
+		b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(ColorError).Render(
+			fmt.Sprintf(", %d unreachable", down)))
+	}
+	b.WriteString("\n\n")
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorWhite)
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  %-20s %-12s %-14s %8s %8s %10s %8s",
+		"Connection", "Env", "Status", "Todo", "Doing", "Failed 1h", "Orphans")))
+	b.WriteString("\n")
+	b.WriteString(lipgloss.NewStyle().Foreground(ColorMuted).Render(
+		"  " + strings.Repeat("─", f.width-4)))
+	b.WriteString("\n")
+
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+	for i, r := range f.rows {
+		cursor := "  "
+		if i == f.cursor {
+			cursor = lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render("► ")
+		}
+
+		env := muted.Render(fmt.Sprintf("%-12s", "-"))
+		if r.environment != "" {
+			env = EnvironmentBadgeStyle(r.environment).Render(fmt.Sprintf("%-12s", truncate(r.environment, 12)))
+		}
+
+		var status, counts string
+		switch {
+		case r.err != nil:
+			status = lipgloss.NewStyle().Foreground(ColorError).Bold(true).Render(fmt.Sprintf("%-14s", "✗ unreachable"))
+			counts = muted.Render(truncate(r.err.Error(), max(f.width-56, 10)))
+		case r.summary == nil:
+			status = muted.Render(fmt.Sprintf("%-14s", "… connecting"))
+		default:
+			status = lipgloss.NewStyle().Foreground(ColorSecondary).Render(
+				fmt.Sprintf("%-14s", fmt.Sprintf("● up %dms", r.latency.Milliseconds())))
+			counts = f.renderCounts(r.summary)
+		}
+
+		b.WriteString(fmt.Sprintf("%s%-20s %s %s %s\n", cursor, truncate(r.name, 20), env, status, counts))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(muted.Render("  enter: open connection · esc/F: back"))
+
+	return b.String()
+}
+
+// renderCounts formats a summary's counts, highlighting non-zero failures
+// and orphans.
+func (f *FleetView) renderCounts(s *db.Summary) string {
+	failed := lipgloss.NewStyle().Foreground(ColorMuted).Render(fmt.Sprintf("%10s", "?"))
+	if s.FailedLastHour != nil {
+		style := lipgloss.NewStyle().Foreground(ColorWhite)
+		if *s.FailedLastHour > 0 {
+			style = StatusStyle(string(db.StatusFailed)).Bold(true)
+		}
+		failed = style.Render(fmt.Sprintf("%10d", *s.FailedLastHour))
+	}
+
+	orphans := lipgloss.NewStyle().Foreground(ColorWhite).Render(fmt.Sprintf("%8d", s.Orphaned))
+	if s.Orphaned > 0 {
+		orphans = lipgloss.NewStyle().Foreground(ColorWarning).Bold(true).Render(fmt.Sprintf("%8d", s.Orphaned))
+	}
+
+	return fmt.Sprintf("%s %s %s %s",
+		StatusStyle(string(db.StatusTodo)).Render(fmt.Sprintf("%8d", s.Todo)),
+		StatusStyle(string(db.StatusDoing)).Render(fmt.Sprintf("%8d", s.Doing)),
+		failed, orphans)
+}
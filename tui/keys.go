@@ -13,12 +13,27 @@ type KeyMap struct {
 	FocusRight   key.Binding
 	Up           key.Binding
 	Down         key.Binding
+	Left         key.Binding
+	Right        key.Binding
 	Enter        key.Binding
 	Back         key.Binding
 	SwitchQueue  key.Binding
 	SwitchConn   key.Binding
 	FilterStatus key.Binding
 	Dashboard    key.Binding
+	Fleet        key.Binding
+	Retry        key.Binding
+	Cancel       key.Binding
+	Abort        key.Binding
+	Select       key.Binding
+	SelectAll    key.Binding
+	Sort         key.Binding
+	Window       key.Binding
+	Query        key.Binding
+	GoTo         key.Binding
+	Defer        key.Binding
+	Purge        key.Binding
+	Confirm      key.Binding
 	Help         key.Binding
 }
 
@@ -61,6 +76,14 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("down", "j"),
 			key.WithHelp("j", "down"),
 		),
+		Left: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("h", "left"),
+		),
+		Right: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("l", "right"),
+		),
 		Enter: key.NewBinding(
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "details"),
@@ -85,6 +108,58 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("D"),
 			key.WithHelp("D", "dashboard"),
 		),
+		Fleet: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "fleet overview"),
+		),
+		Retry: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "retry job"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "cancel job"),
+		),
+		Abort: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "abort job"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "select orphan"),
+		),
+		SelectAll: key.NewBinding(
+			key.WithKeys("*"),
+			key.WithHelp("*", "select all orphans"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort column"),
+		),
+		Query: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "query jobs"),
+		),
+		GoTo: key.NewBinding(
+			key.WithKeys("ctrl+o"),
+			key.WithHelp("ctrl+o", "go to job"),
+		),
+		Defer: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "defer new job"),
+		),
+		Purge: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "purge old jobs"),
+		),
+		Window: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "metrics window"),
+		),
+		Confirm: key.NewBinding(
+			key.WithKeys("y", "Y"),
+			key.WithHelp("y", "confirm"),
+		),
 		Help: key.NewBinding(
 			key.WithKeys("?"),
 			key.WithHelp("?", "help"),
@@ -95,7 +170,15 @@ func DefaultKeyMap() KeyMap {
 // HelpKeys returns a short set of key bindings for the bottom help bar.
 func (k KeyMap) HelpKeys() []key.Binding {
 	return []key.Binding{
-		k.Help, k.Quit, k.Enter, k.Dashboard, k.FilterStatus, k.SwitchConn,
+		k.Help, k.Quit, k.Enter, k.Dashboard, k.FilterStatus, k.Query, k.SwitchConn,
+	}
+}
+
+// MutatingKeys returns the bindings that change job state. They are disabled
+// on read-only connections.
+func (k *KeyMap) MutatingKeys() []*key.Binding {
+	return []*key.Binding{
+		&k.Retry, &k.Cancel, &k.Abort, &k.Select, &k.SelectAll, &k.Defer, &k.Purge,
 	}
 }
 
@@ -104,8 +187,10 @@ func (k KeyMap) AllKeys() []key.Binding {
 	return []key.Binding{
 		k.Quit, k.Help,
 		k.FocusNext, k.FocusPrev, k.FocusLeft, k.FocusRight,
-		k.Up, k.Down, k.Enter, k.Back,
-		k.TabNext, k.TabPrev, k.Dashboard,
-		k.FilterStatus, k.SwitchQueue, k.SwitchConn,
+		k.Up, k.Down, k.Left, k.Right, k.Enter, k.Back,
+		k.TabNext, k.TabPrev, k.Dashboard, k.Fleet,
+		k.FilterStatus, k.Query, k.GoTo, k.SwitchQueue, k.SwitchConn,
+		k.Retry, k.Cancel, k.Abort, k.Defer, k.Purge,
+		k.Select, k.SelectAll, k.Sort, k.Window,
 	}
 }
@@ -35,15 +35,25 @@ func (l *LiveView) SetJobs(jobs []db.Job) {
 	l.viewport.SetContent(l.renderContent())
 }
 
-// AddJob prepends a single job (from LISTEN/NOTIFY).
-func (l *LiveView) AddJob(job db.Job) {
-	// Deduplicate by ID
+// AddJobs prepends a batch of jobs, newest first, skipping any already shown.
+func (l *LiveView) AddJobs(jobs []db.Job) {
+	seen := make(map[int64]bool, len(l.jobs))
 	for _, existing := range l.jobs {
-		if existing.ID == job.ID {
-			return
+		seen[existing.ID] = true
+	}
+
+	var fresh []db.Job
+	for _, job := range jobs {
+		if !seen[job.ID] {
+			seen[job.ID] = true
+			fresh = append(fresh, job)
 		}
 	}
-	l.jobs = append([]db.Job{job}, l.jobs...)
+	if len(fresh) == 0 {
+		return
+	}
+
+	l.jobs = append(fresh, l.jobs...)
 	if len(l.jobs) > maxLiveJobs {
 		l.jobs = l.jobs[:maxLiveJobs]
 	}
@@ -0,0 +1,155 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+// LocksView shows todo jobs grouped by lock with the job blocking each
+// group, and the todo jobs holding queueing locks.
+type LocksView struct {
+	groups        []db.LockGroup
+	queueingLocks []db.QueueingLockHold
+	viewport      viewport.Model
+	width         int
+	height        int
+}
+
+// NewLocksView creates a new lock contention view.
+func NewLocksView() LocksView {
+	return LocksView{}
+}
+
+// SetLocks updates the lock data.
+func (l *LocksView) SetLocks(groups []db.LockGroup, queueingLocks []db.QueueingLockHold) {
+	l.groups = groups
+	l.queueingLocks = queueingLocks
+	l.viewport.SetContent(l.renderContent())
+}
+
+// SetSize updates the viewport dimensions.
+func (l *LocksView) SetSize(width, height int) {
+	l.width = width
+	l.height = height
+	l.viewport.Width = width
+	l.viewport.Height = height
+	l.viewport.SetContent(l.renderContent())
+}
+
+// Update handles messages for the locks view.
+func (l LocksView) Update(msg tea.Msg) (LocksView, tea.Cmd) {
+	var cmd tea.Cmd
+	l.viewport, cmd = l.viewport.Update(msg)
+	return l, cmd
+}
+
+// View renders the locks view.
+func (l LocksView) View() string {
+	return l.viewport.View()
+}
+
+func (l *LocksView) renderContent() string {
+	if len(l.groups) == 0 && len(l.queueingLocks) == 0 {
+		return lipgloss.NewStyle().
+			Foreground(ColorSecondary).
+			Padding(1, 1).
+			Render("No todo jobs are waiting on a lock")
+	}
+
+	now := time.Now()
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorWhite)
+	rule := muted.Render("  " + strings.Repeat("─", l.width-4))
+
+	blocked := 0
+	for _, g := range l.groups {
+		if g.HolderID != nil {
+			blocked++
+		}
+	}
+
+	var b strings.Builder
+
+	// Lock groups
+	if blocked > 0 {
+		b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(ColorWarning).Render(
+			fmt.Sprintf("  ⚠ %d of %d lock(s) blocked by a running job", blocked, len(l.groups))))
+	} else {
+		b.WriteString(headerStyle.Render(fmt.Sprintf("  %d lock(s) with waiting jobs", len(l.groups))))
+	}
+	b.WriteString("\n\n")
+
+	if len(l.groups) > 0 {
+		b.WriteString(headerStyle.Render(
+			fmt.Sprintf("  %-20s %-8s %-10s %-24s %s", "Lock", "Waiting", "Oldest", "Held By", "Held For")))
+		b.WriteString("\n" + rule + "\n")
+
+		for _, g := range l.groups {
+			oldest := "-"
+			if g.OldestWaiting != nil {
+				oldest = formatDuration(now.Sub(*g.OldestWaiting))
+			}
+
+			holder := muted.Render(fmt.Sprintf("%-24s", "free"))
+			heldFor := muted.Render("-")
+			if g.HolderID != nil {
+				h := fmt.Sprintf("#%d %s", *g.HolderID, derefOr(g.HolderTask, ""))
+				if g.HolderWorkerID != nil {
+					h += fmt.Sprintf(" (w#%d)", *g.HolderWorkerID)
+				}
+				holder = StatusStyle("doing").Render(fmt.Sprintf("%-24s", truncate(h, 24)))
+				heldFor = muted.Render("?")
+				if g.HeldSince != nil {
+					heldFor = lipgloss.NewStyle().Foreground(ColorWarning).Render(formatDuration(now.Sub(*g.HeldSince)))
+				}
+			}
+
+			b.WriteString(fmt.Sprintf("  %-20s %-8d %-10s %s %s\n",
+				truncate(g.Lock, 20), g.Waiting, oldest, holder, heldFor))
+		}
+	}
+
+	// Queueing locks
+	b.WriteString("\n")
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  %d queueing lock(s) held", len(l.queueingLocks))))
+	b.WriteString(muted.Render("  · new defers with these locks are rejected"))
+	b.WriteString("\n\n")
+
+	if len(l.queueingLocks) > 0 {
+		b.WriteString(headerStyle.Render(
+			fmt.Sprintf("  %-20s %-8s %-24s %s", "Queueing Lock", "Job", "Task", "Waiting")))
+		b.WriteString("\n" + rule + "\n")
+
+		for _, h := range l.queueingLocks {
+			waiting := "-"
+			if h.ScheduledAt != nil {
+				waiting = formatDuration(now.Sub(*h.ScheduledAt))
+			}
+			b.WriteString(fmt.Sprintf("  %-20s %-8s %-24s %s\n",
+				truncate(h.QueueingLock, 20), fmt.Sprintf("#%d", h.JobID), truncate(h.TaskName, 24), waiting))
+		}
+	}
+
+	return b.String()
+}
+
+// truncate shortens s to at most n characters, marking the cut with "…".
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "…"
+}
+
+func derefOr(s *string, fallback string) string {
+	if s == nil {
+		return fallback
+	}
+	return *s
+}
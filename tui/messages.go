@@ -10,15 +10,22 @@ import (
 // Each carries a gen field to detect stale results from old connections/queues.
 
 type jobsLoadedMsg struct {
-	jobs []db.Job
-	err  error
-	gen  uint64
+	jobs       []db.Job
+	limit      int   // number of jobs requested
+	total      int64 // planner estimate for refreshes; -1 keeps the previous one
+	appendPage bool  // next page for infinite scroll rather than a refresh
+	err        error
+	listing    uint64 // Sidebar.Listing when the fetch started
+	gen        uint64
 }
 
 type statusCountsMsg struct {
-	counts []db.StatusCount
-	err    error
-	gen    uint64
+	counts      []db.StatusCount
+	taskCounts  []db.TaskStatusCount
+	queueCounts []db.QueueStatusCount // set instead of taskCounts for all queues
+	trend       *db.BacklogTrend      // nil when the schema records no events
+	err         error
+	gen         uint64
 }
 
 type orphanedJobsMsg struct {
@@ -27,6 +34,52 @@ type orphanedJobsMsg struct {
 	gen  uint64
 }
 
+type workersLoadedMsg struct {
+	workers []db.Worker
+	err     error
+	gen     uint64
+}
+
+type periodicDefersMsg struct {
+	defers []db.PeriodicDefer
+	err    error
+	gen    uint64
+}
+
+type locksLoadedMsg struct {
+	groups        []db.LockGroup
+	queueingLocks []db.QueueingLockHold
+	err           error
+	gen           uint64
+}
+
+type scheduledJobsMsg struct {
+	buckets []db.ScheduleBucket
+	jobs    []db.Job
+	err     error
+	gen     uint64
+}
+
+type metricsLoadedMsg struct {
+	stats   []db.TaskMetrics
+	err     error
+	request uint64 // MetricsView.Request when the fetch started
+	gen     uint64
+}
+
+type failureGroupsMsg struct {
+	groups []db.FailureGroup
+	err    error
+	gen    uint64
+}
+
+type failureChainMsg struct {
+	jobID  int64
+	events []db.JobEvent
+	err    error
+	gen    uint64
+}
+
 type recentJobsMsg struct {
 	jobs []db.Job
 	err  error
@@ -34,6 +87,15 @@ type recentJobsMsg struct {
 }
 
 type jobDetailMsg struct {
+	job     *db.Job
+	events  []db.JobEvent
+	refresh bool // re-fetch of the open job; keep the current focus
+	err     error
+	gen     uint64
+}
+
+type goToJobMsg struct {
+	id     int64
 	job    *db.Job
 	events []db.JobEvent
 	err    error
@@ -46,11 +108,79 @@ type queuesLoadedMsg struct {
 	gen    uint64
 }
 
+// jobActionMsg reports the outcome of a mutating action (retry, cancel, ...).
+// requested holds the IDs the user asked for, done the IDs actually changed.
+type jobActionMsg struct {
+	verb      string // past tense, e.g. "Retried"
+	requested []int64
+	done      []int64
+	err       error
+	gen       uint64
+}
+
+// deferResultMsg reports the outcome of deferring a job from the form.
+type deferResultMsg struct {
+	id    int64
+	queue string
+	err   error
+	gen   uint64
+}
+
+// purgePreviewMsg carries the dry-run counts shown before a purge.
+type purgePreviewMsg struct {
+	filter db.PurgeFilter
+	counts []db.PurgeCount
+	cutoff time.Time // PurgeJobs deletes only what was counted against it
+	err    error
+	gen    uint64
+}
+
+// purgeResultMsg reports how many jobs a purge deleted. It is shown even
+// when gen is stale, since the jobs are gone either way.
+type purgeResultMsg struct {
+	conn    string // connection the purge ran on
+	deleted int64
+	err     error
+	gen     uint64
+}
+
+// fleetSummaryMsg reports one connection's fleet dashboard poll. client is
+// set when the poll opened the connection's pool.
+type fleetSummaryMsg struct {
+	conn    string
+	client  *db.Client
+	summary *db.Summary
+	latency time.Duration
+	err     error
+}
+
+// fleetTickMsg fires on each fleet dashboard poll interval.
+type fleetTickMsg time.Time
+
 // notificationMsg wraps a LISTEN/NOTIFY event.
 type notificationMsg struct {
+	listener     *db.Listener // listener that delivered it
 	notification db.Notification
 }
 
+// flushNotificationsMsg ends a coalescing window for notified job IDs.
+type flushNotificationsMsg struct {
+	gen uint64
+}
+
+// notifiedJobsMsg carries the jobs fetched for a batch of notifications.
+type notifiedJobsMsg struct {
+	jobs []db.Job
+	err  error
+	gen  uint64
+}
+
+// listenerStateMsg reports a LISTEN connection state change.
+type listenerStateMsg struct {
+	listener *db.Listener
+	state    db.ListenerState
+}
+
 // tickMsg fires on each poll interval.
 type tickMsg time.Time
 
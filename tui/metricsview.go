@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+// metricsWindows are the windows the Metrics tab cycles through.
+var metricsWindows = []time.Duration{
+	5 * time.Minute, 15 * time.Minute, time.Hour, 6 * time.Hour, 24 * time.Hour,
+}
+
+// defaultMetricsWindow is the index of the window shown first (1h).
+const defaultMetricsWindow = 2
+
+// MetricsView shows per-task throughput and wait/run time percentiles
+// over a selectable window.
+type MetricsView struct {
+	stats    []db.TaskMetrics
+	err      error
+	window   int    // index into metricsWindows
+	request  uint64 // bumped when the window changes, to drop stale results
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+// NewMetricsView creates a new metrics view.
+func NewMetricsView() MetricsView {
+	return MetricsView{window: defaultMetricsWindow}
+}
+
+// Window returns the selected window.
+func (m *MetricsView) Window() time.Duration {
+	return metricsWindows[m.window]
+}
+
+// CycleWindow selects the next window, wrapping around, and clears the
+// stats computed for the previous one.
+func (m *MetricsView) CycleWindow() {
+	m.window = (m.window + 1) % len(metricsWindows)
+	m.request++
+	m.stats = nil
+	m.err = nil
+	m.viewport.SetContent(m.renderContent())
+}
+
+// Request returns the token of the current window's fetches.
+func (m *MetricsView) Request() uint64 {
+	return m.request
+}
+
+// SetStats updates the metrics. err is shown in place of the table, for
+// schemas without events.
+func (m *MetricsView) SetStats(stats []db.TaskMetrics, err error) {
+	m.stats = stats
+	m.err = err
+	m.viewport.SetContent(m.renderContent())
+}
+
+// SetSize updates the viewport dimensions.
+func (m *MetricsView) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.viewport.Width = width
+	m.viewport.Height = height
+	m.viewport.SetContent(m.renderContent())
+}
+
+// Update handles messages for the metrics view.
+func (m MetricsView) Update(msg tea.Msg) (MetricsView, tea.Cmd) {
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the metrics view.
+func (m MetricsView) View() string {
+	return m.viewport.View()
+}
+
+func (m *MetricsView) renderContent() string {
+	if m.err != nil {
+		return lipgloss.NewStyle().
+			Foreground(ColorMuted).
+			Padding(1, 1).
+			Render(m.err.Error())
+	}
+
+	var b strings.Builder
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorWhite)
+
+	// Window selector
+	b.WriteString(headerStyle.Render("  Window: "))
+	for i, w := range metricsWindows {
+		label := formatWindow(w)
+		if i == m.window {
+			b.WriteString(lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render("[" + label + "]"))
+		} else {
+			b.WriteString(muted.Render(" " + label + " "))
+		}
+		b.WriteString(" ")
+	}
+	b.WriteString("\n\n")
+
+	if len(m.stats) == 0 || m.stats[0].Finished == 0 && m.stats[0].Wait == nil {
+		b.WriteString(muted.Render("  No job activity in the last " + formatWindow(m.Window())))
+		return b.String()
+	}
+
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  %-24s %8s %7s %7s   %-20s %-20s",
+		"Task", "Finished", "Failed", "/min", "Wait p50/p95/p99", "Run p50/p95/p99")))
+	b.WriteString("\n")
+	b.WriteString(muted.Render("  " + strings.Repeat("─", m.width-4)))
+	b.WriteString("\n")
+
+	for i, s := range m.stats {
+		task := s.TaskName
+		style := lipgloss.NewStyle().Foreground(ColorWhite)
+		if i == 0 {
+			task = "(all tasks)"
+			style = style.Bold(true)
+		}
+
+		failed := fmt.Sprintf("%7d", s.Failed)
+		if s.Failed > 0 {
+			failed = StatusStyle(string(db.StatusFailed)).Render(failed)
+		}
+
+		b.WriteString(fmt.Sprintf("  %s %s %s %s   %s %s\n",
+			style.Render(fmt.Sprintf("%-24s", truncate(task, 24))),
+			style.Render(fmt.Sprintf("%8d", s.Finished)),
+			failed,
+			style.Render(fmt.Sprintf("%7.1f", s.PerMinute)),
+			fmt.Sprintf("%-20s", formatPercentiles(s.Wait)),
+			fmt.Sprintf("%-20s", formatPercentiles(s.Run))))
+
+		if i == 0 && len(m.stats) > 1 {
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(muted.Render("  w: change window · wait: deferred → started · run: started → finished"))
+
+	return b.String()
+}
+
+// formatPercentiles renders p50/p95/p99, or "-" when there were no samples.
+func formatPercentiles(p *db.Percentiles) string {
+	if p == nil {
+		return "-"
+	}
+	return formatLatency(p.P50) + "/" + formatLatency(p.P95) + "/" + formatLatency(p.P99)
+}
+
+// formatLatency is formatDuration with sub-second precision for short
+// durations.
+func formatLatency(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < 10*time.Second:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	default:
+		return formatDuration(d)
+	}
+}
+
+// formatWindow renders a window as 5m, 1h or 24h.
+func formatWindow(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
@@ -11,9 +11,15 @@ import (
 	"github.com/matthewmyrick/procrastinate-cli/db"
 )
 
+// orphanedHeaderLines is the number of lines rendered above the first job row.
+const orphanedHeaderLines = 4
+
 // OrphanedView shows jobs that appear stuck or abandoned.
+// Jobs can be selected for bulk requeue/fail/cancel.
 type OrphanedView struct {
 	jobs     []db.Job
+	cursor   int
+	selected map[int64]bool
 	viewport viewport.Model
 	width    int
 	height   int
@@ -21,13 +27,95 @@ type OrphanedView struct {
 
 // NewOrphanedView creates a new orphaned jobs view.
 func NewOrphanedView() OrphanedView {
-	return OrphanedView{}
+	return OrphanedView{selected: make(map[int64]bool)}
 }
 
-// SetJobs updates the orphaned job data.
+// SetJobs updates the orphaned job data, dropping selections for jobs that
+// are no longer orphaned.
 func (o *OrphanedView) SetJobs(jobs []db.Job) {
 	o.jobs = jobs
+
+	present := make(map[int64]bool, len(jobs))
+	for _, j := range jobs {
+		present[j.ID] = true
+	}
+	for id := range o.selected {
+		if !present[id] {
+			delete(o.selected, id)
+		}
+	}
+	if o.cursor >= len(jobs) {
+		o.cursor = max(len(jobs)-1, 0)
+	}
+
+	o.refresh()
+}
+
+// MoveCursor moves the highlighted row by delta, clamped to the list.
+func (o *OrphanedView) MoveCursor(delta int) {
+	o.cursor = min(max(o.cursor+delta, 0), max(len(o.jobs)-1, 0))
+	o.refresh()
+}
+
+// ToggleSelected selects or deselects the highlighted job.
+func (o *OrphanedView) ToggleSelected() {
+	if o.cursor >= len(o.jobs) {
+		return
+	}
+	id := o.jobs[o.cursor].ID
+	if o.selected[id] {
+		delete(o.selected, id)
+	} else {
+		o.selected[id] = true
+	}
+	o.refresh()
+}
+
+// ToggleAll selects every job, or clears the selection if all are selected.
+func (o *OrphanedView) ToggleAll() {
+	if len(o.selected) == len(o.jobs) {
+		clear(o.selected)
+	} else {
+		for _, j := range o.jobs {
+			o.selected[j.ID] = true
+		}
+	}
+	o.refresh()
+}
+
+// ClearSelection deselects all jobs.
+func (o *OrphanedView) ClearSelection() {
+	clear(o.selected)
+	o.refresh()
+}
+
+// Targets returns the jobs a bulk action applies to: the selected jobs, or
+// the highlighted job when nothing is selected.
+func (o *OrphanedView) Targets() []db.Job {
+	var targets []db.Job
+	for _, j := range o.jobs {
+		if o.selected[j.ID] {
+			targets = append(targets, j)
+		}
+	}
+	if len(targets) == 0 && o.cursor < len(o.jobs) {
+		targets = append(targets, o.jobs[o.cursor])
+	}
+	return targets
+}
+
+// refresh re-renders the content and scrolls to keep the cursor visible.
+func (o *OrphanedView) refresh() {
 	o.viewport.SetContent(o.renderContent())
+	if len(o.jobs) == 0 {
+		return
+	}
+	line := orphanedHeaderLines + o.cursor
+	if line < o.viewport.YOffset {
+		o.viewport.SetYOffset(line)
+	} else if line >= o.viewport.YOffset+o.viewport.Height {
+		o.viewport.SetYOffset(line - o.viewport.Height + 1)
+	}
 }
 
 // SetSize updates the viewport dimensions.
@@ -36,7 +124,7 @@ func (o *OrphanedView) SetSize(width, height int) {
 	o.height = height
 	o.viewport.Width = width
 	o.viewport.Height = height
-	o.viewport.SetContent(o.renderContent())
+	o.refresh()
 }
 
 // Update handles messages for the orphaned view.
@@ -65,21 +153,34 @@ func (o *OrphanedView) renderContent() string {
 	warningStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorWarning)
 	b.WriteString(warningStyle.Render(
 		fmt.Sprintf("  ⚠ %d orphaned job(s) found", len(o.jobs))))
+	if len(o.selected) > 0 {
+		b.WriteString(lipgloss.NewStyle().Foreground(ColorMuted).Render(
+			fmt.Sprintf("  · %d selected", len(o.selected))))
+	}
 	b.WriteString("\n\n")
 
 	// Column headers
 	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorWhite)
 	b.WriteString(headerStyle.Render(
-		fmt.Sprintf("  %-8s %-20s %-10s %-10s %s", "ID", "Task", "Status", "Stuck For", "Worker")))
+		fmt.Sprintf("    %-8s %-20s %-10s %-10s %s", "ID", "Task", "Status", "Stuck For", "Worker")))
 	b.WriteString("\n")
 	b.WriteString(lipgloss.NewStyle().Foreground(ColorMuted).Render(
 		"  " + strings.Repeat("─", o.width-4)))
 	b.WriteString("\n")
 
 	now := time.Now()
-	for _, job := range o.jobs {
+	for i, job := range o.jobs {
 		id := fmt.Sprintf("#%d", job.ID)
 
+		cursor := "  "
+		if i == o.cursor {
+			cursor = lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render("► ")
+		}
+		mark := "  "
+		if o.selected[job.ID] {
+			mark = lipgloss.NewStyle().Foreground(ColorSecondary).Bold(true).Render("✓ ")
+		}
+
 		task := job.TaskName
 		maxTask := 20
 		if len(task) > maxTask {
@@ -101,7 +202,7 @@ func (o *OrphanedView) renderContent() string {
 				Render(fmt.Sprintf("#%d (dead)", *job.WorkerID))
 		}
 
-		b.WriteString(fmt.Sprintf("  %-8s %-20s %s %s %s\n", id, task, status, stuckFor, worker))
+		b.WriteString(fmt.Sprintf("%s%s%-8s %-20s %s %s %s\n", cursor, mark, id, task, status, stuckFor, worker))
 	}
 
 	return b.String()
@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+// PeriodicView lists periodic tasks with their last defer and how far
+// behind schedule each one is.
+type PeriodicView struct {
+	defers   []db.PeriodicDefer
+	err      error
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+// NewPeriodicView creates a new periodic tasks view.
+func NewPeriodicView() PeriodicView {
+	return PeriodicView{}
+}
+
+// SetDefers updates the periodic task data. err is shown in place of the
+// list, for schemas without periodic defers.
+func (p *PeriodicView) SetDefers(defers []db.PeriodicDefer, err error) {
+	p.defers = defers
+	p.err = err
+	p.viewport.SetContent(p.renderContent())
+}
+
+// SetSize updates the viewport dimensions.
+func (p *PeriodicView) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+	p.viewport.Width = width
+	p.viewport.Height = height
+	p.viewport.SetContent(p.renderContent())
+}
+
+// Update handles messages for the periodic view.
+func (p PeriodicView) Update(msg tea.Msg) (PeriodicView, tea.Cmd) {
+	var cmd tea.Cmd
+	p.viewport, cmd = p.viewport.Update(msg)
+	return p, cmd
+}
+
+// View renders the periodic view.
+func (p PeriodicView) View() string {
+	return p.viewport.View()
+}
+
+func (p *PeriodicView) renderContent() string {
+	if p.err != nil {
+		return lipgloss.NewStyle().
+			Foreground(ColorMuted).
+			Padding(1, 1).
+			Render(p.err.Error())
+	}
+	if len(p.defers) == 0 {
+		return lipgloss.NewStyle().
+			Foreground(ColorMuted).
+			Padding(1, 1).
+			Render("No periodic tasks have been deferred")
+	}
+
+	now := time.Now()
+	behind := 0
+	for _, d := range p.defers {
+		if d.MissedRuns(now) > 0 {
+			behind++
+		}
+	}
+
+	var b strings.Builder
+
+	// Summary
+	if behind > 0 {
+		b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(ColorWarning).Render(
+			fmt.Sprintf("  ⚠ %d of %d periodic task(s) missed a run", behind, len(p.defers))))
+	} else {
+		b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(ColorWhite).Render(
+			fmt.Sprintf("  %d periodic task(s), all on schedule", len(p.defers))))
+	}
+	b.WriteString("\n\n")
+
+	// Column headers
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorWhite)
+	b.WriteString(headerStyle.Render(
+		fmt.Sprintf("  %-24s %-10s %-9s %-10s %-12s %s", "Task", "Last Defer", "Interval", "Next Run", "Behind", "Job")))
+	b.WriteString("\n")
+	b.WriteString(lipgloss.NewStyle().Foreground(ColorMuted).Render(
+		"  " + strings.Repeat("─", p.width-4)))
+	b.WriteString("\n")
+
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+	for _, d := range p.defers {
+		task := d.TaskName
+		if d.PeriodicID != "" {
+			task += " [" + d.PeriodicID + "]"
+		}
+		if len(task) > 24 {
+			task = task[:23] + "…"
+		}
+
+		last := muted.Render(fmt.Sprintf("%-10s", formatDuration(now.Sub(d.LastDefer))+" ago"))
+
+		interval, next := "?", "?"
+		if d.Interval > 0 {
+			interval = formatDuration(d.Interval)
+			nextRun, _ := d.NextRun()
+			next = formatDuration(nextRun.Sub(now))
+			if !nextRun.After(now) {
+				next = "due"
+			}
+		}
+
+		late := lipgloss.NewStyle().Foreground(ColorSecondary).Render(fmt.Sprintf("%-12s", "on time"))
+		switch missed := d.MissedRuns(now); {
+		case d.Interval <= 0:
+			late = muted.Render(fmt.Sprintf("%-12s", "unknown"))
+		case missed > 0:
+			late = lipgloss.NewStyle().Foreground(ColorError).Bold(true).Render(
+				fmt.Sprintf("%-12s", fmt.Sprintf("%s (%d)", formatDuration(d.Behind(now)), missed)))
+		case d.Behind(now) > 0:
+			late = lipgloss.NewStyle().Foreground(ColorWarning).Render(
+				fmt.Sprintf("%-12s", formatDuration(d.Behind(now))))
+		}
+
+		job := muted.Render("deleted")
+		if d.JobID != nil {
+			job = fmt.Sprintf("#%d", *d.JobID)
+			if d.JobStatus != nil {
+				job += " " + StatusStyle(string(*d.JobStatus)).Render(string(*d.JobStatus))
+			}
+		}
+
+		b.WriteString(fmt.Sprintf("  %-24s %s %-9s %-10s %s %s\n", task, last, interval, next, late, job))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(muted.Render(
+		"  Intervals are inferred from the previous run; behind (n) counts missed runs."))
+
+	return b.String()
+}
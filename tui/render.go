@@ -5,22 +5,63 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/matthewmyrick/procrastinate-cli/config"
+	"github.com/matthewmyrick/procrastinate-cli/db"
 )
 
 func (a *App) renderTopBar() string {
-	queueLabel := TopBarQueueStyle.Render(fmt.Sprintf("Queue: %s", a.currentQueue))
-	connLabel := TopBarConnStyle.Render(fmt.Sprintf("Connection: %s", a.currentConn))
+	// Production connections get a tinted bar; every segment carries the
+	// background so inner resets don't punch holes in it.
+	style, queueStyle, connStyle := TopBarStyle, TopBarQueueStyle, TopBarConnStyle
+	gapStyle := lipgloss.NewStyle()
+	if a.environment == config.EnvironmentProduction {
+		style = TopBarProductionStyle
+		queueStyle = queueStyle.Background(ColorProductionBar)
+		connStyle = connStyle.Background(ColorProductionBar)
+		gapStyle = gapStyle.Background(ColorProductionBar)
+	}
+
+	queueLabel := queueStyle.Render(fmt.Sprintf("Queue: %s", a.queueLabel()))
+	connLabel := connStyle.Render(fmt.Sprintf("Connection: %s", a.currentConn))
+
+	// Environment and read-only badges sit next to the connection name
+	var badges string
+	if a.environment != "" {
+		badges += EnvironmentBadgeStyle(a.environment).Render(strings.ToUpper(a.environment)) + gapStyle.Render(" ")
+	}
+	if a.readOnly {
+		badges += ReadOnlyBadgeStyle.Render("READ-ONLY") + gapStyle.Render(" ")
+	}
+
+	live := gapStyle.Render(" ") + a.renderLiveIndicator(gapStyle)
 
-	gap := a.width - lipgloss.Width(queueLabel) - lipgloss.Width(connLabel) - 2
+	gap := a.width - lipgloss.Width(queueLabel) - lipgloss.Width(live) - lipgloss.Width(badges) - lipgloss.Width(connLabel) - 2
 	if gap < 1 {
 		gap = 1
 	}
 
-	return TopBarStyle.Width(a.width).Render(
-		queueLabel + strings.Repeat(" ", gap) + connLabel,
+	return style.Width(a.width).Render(
+		queueLabel + live + gapStyle.Render(strings.Repeat(" ", gap)) + badges + connLabel,
 	)
 }
 
+// renderLiveIndicator shows whether updates arrive via LISTEN/NOTIFY or only
+// through polling.
+func (a *App) renderLiveIndicator(base lipgloss.Style) string {
+	if !a.connected {
+		return ""
+	}
+	style := base.Bold(true)
+	switch {
+	case a.listener == nil || a.listenerState == db.ListenerDegraded:
+		return style.Foreground(ColorError).Render(fmt.Sprintf("○ polling every %s", a.config.PollInterval))
+	case a.listenerState == db.ListenerReconnecting:
+		return style.Foreground(ColorWarning).Render("◌ reconnecting")
+	default:
+		return style.Foreground(ColorSecondary).Render("● live")
+	}
+}
+
 func (a *App) renderDetail(width, height int) string {
 	style := DetailStyle
 	if a.focus == focusDetail {
@@ -31,6 +72,10 @@ func (a *App) renderDetail(width, height int) string {
 	if !a.connected {
 		statusMsg := lipgloss.NewStyle().Foreground(ColorMuted).Render(
 			"Not connected — press C to switch connections")
+		if a.lastError != nil {
+			errMsg := ErrorBannerStyle.Width(width - 4).Render(a.lastError.Error())
+			statusMsg = lipgloss.JoinVertical(lipgloss.Center, statusMsg, "", errMsg)
+		}
 		centered := lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, statusMsg)
 		return style.Width(width).Height(height).Render(centered)
 	}
@@ -54,6 +99,18 @@ func (a *App) renderDetail(width, height int) string {
 		tabContent = a.liveView.View()
 	case TabOrphaned:
 		tabContent = a.orphanedView.View()
+	case TabWorkers:
+		tabContent = a.workersView.View()
+	case TabPeriodic:
+		tabContent = a.periodicView.View()
+	case TabLocks:
+		tabContent = a.locksView.View()
+	case TabScheduled:
+		tabContent = a.scheduledView.View()
+	case TabMetrics:
+		tabContent = a.metricsView.View()
+	case TabFailures:
+		tabContent = a.failuresView.View()
 	}
 
 	var parts []string
@@ -68,11 +125,69 @@ func (a *App) renderDetail(width, height int) string {
 	)
 }
 
+// renderToastOverlay draws the toast right-aligned over the top bar,
+// leaving the rest of the screen visible.
 func (a *App) renderToastOverlay(base string) string {
-	rendered := ToastStyle.Render(a.toast)
-	return lipgloss.Place(a.width, a.height, lipgloss.Right, lipgloss.Top, rendered,
+	style := ToastSuccessStyle
+	if a.toastErr {
+		style = ToastStyle
+	}
+	rendered := style.MaxWidth(a.width).Render(a.toast)
+	toastLine := lipgloss.PlaceHorizontal(a.width, lipgloss.Right, rendered,
 		lipgloss.WithWhitespaceChars(" "),
 	)
+
+	lines := strings.Split(base, "\n")
+	lines[0] = toastLine
+	return strings.Join(lines, "\n")
+}
+
+func (a *App) renderConfirm() string {
+	var b strings.Builder
+
+	b.WriteString(TitleStyle.Render(a.confirmTitle))
+	b.WriteString("\n\n")
+
+	for _, line := range a.confirmLines {
+		b.WriteString(ValueStyle.Render(line) + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(lipgloss.NewStyle().Foreground(ColorMuted).Render("y/enter confirm · n/esc cancel"))
+
+	width := 60
+	if width > a.width-10 {
+		width = a.width - 10
+	}
+
+	return OverlayStyle.Width(width).Render(b.String())
+}
+
+// renderPrompt renders the text prompt overlay with a hint and key help
+// below the input.
+func (a *App) renderPrompt(title, hint, keys string) string {
+	var b strings.Builder
+
+	b.WriteString(TitleStyle.Render(title))
+	b.WriteString("\n\n")
+	b.WriteString(a.promptInput.View())
+	b.WriteString("\n\n")
+
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+	if a.promptErr != nil {
+		b.WriteString(lipgloss.NewStyle().Foreground(ColorError).Render(a.promptErr.Error()))
+		b.WriteString("\n\n")
+	}
+	b.WriteString(muted.Render(hint))
+	b.WriteString("\n")
+	b.WriteString(muted.Render(keys))
+
+	width := 80
+	if width > a.width-10 {
+		width = a.width - 10
+	}
+
+	return OverlayStyle.Width(width).Render(b.String())
 }
 
 func (a *App) renderPicker(title string, items []string, selected int) string {
@@ -122,6 +237,9 @@ func (a *App) renderHelpOverlay() string {
 	descStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#DDDDDD"))
 
 	for _, k := range a.keys.AllKeys() {
+		if !k.Enabled() {
+			continue
+		}
 		h := k.Help()
 		b.WriteString(fmt.Sprintf("  %s %s\n", keyStyle.Render(h.Key), descStyle.Render(h.Desc)))
 	}
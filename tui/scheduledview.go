@@ -0,0 +1,153 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+// scheduledJobLimit caps how many upcoming jobs are listed; the histogram
+// still counts all of them.
+const scheduledJobLimit = 200
+
+// ScheduledView shows todo jobs scheduled in the future, bucketed by how
+// soon they are due, with a histogram of the bucket counts.
+type ScheduledView struct {
+	buckets  []db.ScheduleBucket
+	jobs     []db.Job
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+// NewScheduledView creates a new scheduled jobs view.
+func NewScheduledView() ScheduledView {
+	return ScheduledView{}
+}
+
+// SetJobs updates the bucket counts and the listed jobs.
+func (s *ScheduledView) SetJobs(buckets []db.ScheduleBucket, jobs []db.Job) {
+	s.buckets = buckets
+	s.jobs = jobs
+	s.viewport.SetContent(s.renderContent())
+}
+
+// SetSize updates the viewport dimensions.
+func (s *ScheduledView) SetSize(width, height int) {
+	s.width = width
+	s.height = height
+	s.viewport.Width = width
+	s.viewport.Height = height
+	s.viewport.SetContent(s.renderContent())
+}
+
+// Update handles messages for the scheduled view.
+func (s ScheduledView) Update(msg tea.Msg) (ScheduledView, tea.Cmd) {
+	var cmd tea.Cmd
+	s.viewport, cmd = s.viewport.Update(msg)
+	return s, cmd
+}
+
+// View renders the scheduled view.
+func (s ScheduledView) View() string {
+	return s.viewport.View()
+}
+
+func (s *ScheduledView) renderContent() string {
+	var total, peak int64
+	for _, b := range s.buckets {
+		total += b.Count
+		peak = max(peak, b.Count)
+	}
+	if total == 0 {
+		return lipgloss.NewStyle().
+			Foreground(ColorMuted).
+			Padding(1, 1).
+			Render("No jobs scheduled in the future")
+	}
+
+	var b strings.Builder
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorWhite)
+
+	// Histogram
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  %d job(s) scheduled", total)))
+	b.WriteString("\n\n")
+
+	maxBarWidth := max(s.width-30, 10)
+	barStyle := StatusStyle(string(db.StatusTodo))
+	for _, bucket := range s.buckets {
+		barWidth := 0
+		if bucket.Count > 0 {
+			barWidth = max(int(float64(bucket.Count)/float64(peak)*float64(maxBarWidth)), 1)
+		}
+		b.WriteString(fmt.Sprintf("  %-10s %8d   %s\n",
+			bucket.Label, bucket.Count, barStyle.Render(strings.Repeat("█", barWidth))))
+	}
+
+	// Upcoming jobs, grouped under their bucket
+	b.WriteString("\n")
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  %-8s %-24s %-10s %-9s %s", "ID", "Task", "Due In", "At", "Attempts")))
+	b.WriteString("\n")
+	b.WriteString(muted.Render("  " + strings.Repeat("─", s.width-4)))
+	b.WriteString("\n")
+
+	now := time.Now()
+	current := -1
+	for _, job := range s.jobs {
+		if job.ScheduledAt == nil {
+			continue
+		}
+		due := job.ScheduledAt.Sub(now)
+		if i := s.bucketIndex(due); i != current {
+			current = i
+			b.WriteString(lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render(
+				fmt.Sprintf("  ▸ %s", s.buckets[i].Label)))
+			b.WriteString("\n")
+		}
+
+		attempts := muted.Render("-")
+		if job.Attempts > 0 {
+			attempts = lipgloss.NewStyle().Foreground(ColorWarning).Render(fmt.Sprintf("retry #%d", job.Attempts))
+		}
+
+		b.WriteString(fmt.Sprintf("  %-8s %-24s %-10s %s %s\n",
+			fmt.Sprintf("#%d", job.ID),
+			truncate(job.TaskName, 24),
+			formatDuration(max(due, 0)),
+			muted.Render(fmt.Sprintf("%-9s", formatScheduleTime(*job.ScheduledAt, now))),
+			attempts))
+	}
+
+	if total > int64(len(s.jobs)) {
+		b.WriteString("\n")
+		b.WriteString(muted.Render(fmt.Sprintf("  showing the first %d of %d", len(s.jobs), total)))
+	}
+
+	return b.String()
+}
+
+// bucketIndex returns the bucket a job due in d falls into.
+func (s *ScheduledView) bucketIndex(d time.Duration) int {
+	for i, bucket := range s.buckets {
+		if bucket.Within == 0 || d <= bucket.Within {
+			return i
+		}
+	}
+	return len(s.buckets) - 1
+}
+
+// formatScheduleTime shows the clock time for jobs due today and the date
+// for later ones.
+func formatScheduleTime(t, now time.Time) string {
+	t = t.Local()
+	if y, m, d := now.Date(); t.Year() == y && t.Month() == m && t.Day() == d {
+		return t.Format("15:04:05")
+	}
+	return t.Format("Jan 02")
+}
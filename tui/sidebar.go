@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
@@ -11,10 +12,19 @@ import (
 	"github.com/matthewmyrick/procrastinate-cli/db"
 )
 
-var (
-	filterOptions = []string{"", "doing", "todo", "succeeded", "failed", "cancelled"}
-	filterLabels  = []string{"All", "Doing", "Todo", "Succeeded", "Failed", "Cancelled"}
-)
+// jobPageSize is how many jobs the sidebar loads per page.
+const jobPageSize = 100
+
+// sidebarQueueWidth is the width of the queue column in all-queues mode.
+const sidebarQueueWidth = 8
+
+// loadMoreThreshold is how close to the bottom the cursor must get before
+// the next page is requested.
+const loadMoreThreshold = 10
+
+// defaultFilterOptions are the status filters offered before the schema's
+// statuses are known.
+var defaultFilterOptions = []string{"", "doing", "todo", "succeeded", "failed", "cancelled"}
 
 // jobItem wraps a db.Job to implement list.Item.
 type jobItem struct {
@@ -24,7 +34,9 @@ type jobItem struct {
 func (j jobItem) FilterValue() string { return j.job.TaskName }
 
 // jobItemDelegate renders each job in the sidebar list.
-type jobItemDelegate struct{}
+type jobItemDelegate struct {
+	showQueue bool // prefix each job with its queue (all-queues mode)
+}
 
 func (d jobItemDelegate) Height() int                             { return 1 }
 func (d jobItemDelegate) Spacing() int                            { return 0 }
@@ -40,8 +52,14 @@ func (d jobItemDelegate) Render(w io.Writer, m list.Model, index int, item list.
 	task := ji.job.TaskName
 	status := string(ji.job.Status)
 
+	// Queue column, only shown across all queues
+	queue := ""
+	if d.showQueue {
+		queue = fmt.Sprintf("%-*s ", sidebarQueueWidth, truncate(ji.job.QueueName, sidebarQueueWidth))
+	}
+
 	// Truncate task name to fit
-	maxTask := m.Width() - len(id) - len(status) - 6
+	maxTask := m.Width() - len(id) - len(queue) - len(status) - 6
 	if maxTask < 4 {
 		maxTask = 4
 	}
@@ -50,8 +68,9 @@ func (d jobItemDelegate) Render(w io.Writer, m list.Model, index int, item list.
 	}
 
 	statusRendered := StatusStyle(status).Render(status)
+	queueRendered := lipgloss.NewStyle().Foreground(ColorMuted).Render(queue)
 
-	line := fmt.Sprintf(" %s %s %s", id, task, statusRendered)
+	line := fmt.Sprintf(" %s %s%s %s", id, queueRendered, task, statusRendered)
 
 	if index == m.Index() {
 		line = lipgloss.NewStyle().
@@ -59,7 +78,7 @@ func (d jobItemDelegate) Render(w io.Writer, m list.Model, index int, item list.
 			Foreground(ColorWhite).
 			Background(ColorDim).
 			Width(m.Width()).
-			Render(fmt.Sprintf(" ► %s %s %s", id, task, status))
+			Render(fmt.Sprintf(" ► %s %s%s %s", id, queue, task, status))
 	}
 
 	fmt.Fprint(w, line)
@@ -67,12 +86,22 @@ func (d jobItemDelegate) Render(w io.Writer, m list.Model, index int, item list.
 
 // Sidebar is the left-pane job list.
 type Sidebar struct {
-	list        list.Model
-	jobs        []db.Job
-	focused     bool
-	width       int
-	height      int
-	filterIndex int // index into filterOptions
+	list          list.Model
+	jobs          []db.Job
+	focused       bool
+	width         int
+	height        int
+	filterOptions []string // status filter values; "" means all
+	filterIndex   int      // index into filterOptions
+	task          string   // only jobs of this task; empty means all
+	worker        *int64   // only this worker's doing jobs, across all queues
+	query         *db.Query
+
+	// Pagination state
+	total       int64 // planner estimate of matching jobs
+	exhausted   bool  // the last page came back short; nothing more to load
+	loadingMore bool
+	listing     uint64 // bumped by ResetPaging, to drop pages of an old filter
 }
 
 // NewSidebar creates a new sidebar with the given dimensions.
@@ -82,13 +111,14 @@ func NewSidebar(width, height int) Sidebar {
 	l.SetShowTitle(false)
 	l.SetShowStatusBar(false)
 	l.SetShowHelp(false)
-	l.SetFilteringEnabled(true)
+	l.SetFilteringEnabled(false) // "/" opens the query prompt instead
 	l.DisableQuitKeybindings()
 
 	return Sidebar{
-		list:   l,
-		width:  width,
-		height: height,
+		list:          l,
+		width:         width,
+		height:        height,
+		filterOptions: defaultFilterOptions,
 	}
 }
 
@@ -103,6 +133,117 @@ func (s *Sidebar) SetJobs(jobs []db.Job) tea.Cmd {
 	return s.list.SetItems(items)
 }
 
+// RefreshFirstPage merges a fresh first page into the loaded jobs. Jobs
+// loaded from later pages that sort after the fresh page are kept, so a
+// refresh costs one page however far the user has scrolled. total < 0 keeps
+// the previous estimate.
+func (s *Sidebar) RefreshFirstPage(jobs []db.Job, limit int, total int64) tea.Cmd {
+	if total >= 0 {
+		s.total = total
+	}
+	if len(jobs) < limit || len(s.jobs) <= limit {
+		s.exhausted = len(jobs) < limit
+		return s.SetJobs(jobs)
+	}
+
+	fresh := make(map[int64]bool, len(jobs))
+	for _, j := range jobs {
+		fresh[j.ID] = true
+	}
+	filter := db.JobFilter{Status: s.CurrentFilter()}
+	last := db.CursorAfter(jobs[len(jobs)-1])
+	merged := append([]db.Job(nil), jobs...)
+	for _, j := range s.jobs {
+		if !fresh[j.ID] && filter.SortsAfter(j, last) {
+			merged = append(merged, j)
+		}
+	}
+	return s.SetJobs(merged)
+}
+
+// AppendPage adds the next page of jobs, skipping any already loaded.
+func (s *Sidebar) AppendPage(jobs []db.Job, limit int) tea.Cmd {
+	s.loadingMore = false
+	s.exhausted = len(jobs) < limit
+
+	seen := make(map[int64]bool, len(s.jobs))
+	for _, j := range s.jobs {
+		seen[j.ID] = true
+	}
+	merged := append([]db.Job(nil), s.jobs...)
+	for _, j := range jobs {
+		if !seen[j.ID] {
+			merged = append(merged, j)
+		}
+	}
+	return s.SetJobs(merged)
+}
+
+// ResetPaging drops loaded jobs so the next fetch starts from the first page.
+func (s *Sidebar) ResetPaging() tea.Cmd {
+	s.listing++
+	s.total = 0
+	s.exhausted = false
+	s.loadingMore = false
+	return s.SetJobs(nil)
+}
+
+// Listing returns the token of the current filter's page fetches.
+func (s *Sidebar) Listing() uint64 {
+	return s.listing
+}
+
+// Len returns the number of loaded jobs.
+func (s *Sidebar) Len() int {
+	return len(s.jobs)
+}
+
+// NeedsMore reports whether the cursor is near the bottom of the loaded jobs
+// and another page should be fetched.
+func (s *Sidebar) NeedsMore() bool {
+	if s.loadingMore || s.exhausted || len(s.jobs) == 0 {
+		return false
+	}
+	return s.list.Index() >= len(s.list.VisibleItems())-loadMoreThreshold
+}
+
+// StartLoadingMore marks a page fetch as in flight and returns the cursor to
+// continue from.
+func (s *Sidebar) StartLoadingMore() db.JobCursor {
+	s.loadingMore = true
+	return db.CursorAfter(s.jobs[len(s.jobs)-1])
+}
+
+// UpsertJobs replaces jobs already in the list and prepends new ones that
+// match the current status filter. New jobs are left to the next full fetch
+// while a query is set, since only the database can evaluate it. The next
+// full fetch restores ordering.
+func (s *Sidebar) UpsertJobs(jobs []db.Job) tea.Cmd {
+	index := make(map[int64]int, len(s.jobs))
+	for i, j := range s.jobs {
+		index[j.ID] = i
+	}
+
+	updated := append([]db.Job(nil), s.jobs...)
+	var fresh []db.Job
+	filter := s.CurrentFilter()
+	for _, j := range jobs {
+		if i, ok := index[j.ID]; ok {
+			updated[i] = j
+		} else if s.query != nil {
+			continue
+		} else if s.worker != nil && (j.WorkerID == nil || *j.WorkerID != *s.worker) {
+			continue
+		} else if s.task != "" && j.TaskName != s.task {
+			continue
+		} else if filter == "" || string(j.Status) == filter {
+			fresh = append(fresh, j)
+		}
+	}
+
+	return s.SetJobs(append(fresh, updated...))
+}
+
 // SelectedJob returns the currently highlighted job, or nil if none.
 func (s *Sidebar) SelectedJob() *db.Job {
 	item := s.list.SelectedItem()
@@ -115,7 +256,80 @@ func (s *Sidebar) SelectedJob() *db.Job {
 
 // CurrentFilter returns the current status filter value (empty string = All).
 func (s *Sidebar) CurrentFilter() string {
-	return filterOptions[s.filterIndex]
+	return s.filterOptions[s.filterIndex]
+}
+
+// SetStatuses offers the given statuses, plus All, as status filters.
+// The current filter is kept if it still exists.
+func (s *Sidebar) SetStatuses(statuses []db.JobStatus) {
+	current := s.CurrentFilter()
+	options := []string{""}
+	for _, st := range statuses {
+		options = append(options, string(st))
+	}
+	s.filterOptions = options
+	s.filterIndex = max(slices.Index(options, current), 0)
+}
+
+// FilterLabels returns the display labels of the status filters.
+func (s *Sidebar) FilterLabels() []string {
+	labels := make([]string, len(s.filterOptions))
+	for i, opt := range s.filterOptions {
+		labels[i] = filterLabel(opt)
+	}
+	return labels
+}
+
+// SetStatusFilter selects a status filter by index and clears the task and
+// worker filters.
+func (s *Sidebar) SetStatusFilter(index int) {
+	s.filterIndex = index
+	s.task = ""
+	s.worker = nil
+}
+
+// SetTaskFilter limits the list to one task in the given status ("" for
+// all statuses). Statuses the schema does not have select All.
+func (s *Sidebar) SetTaskFilter(task, status string) {
+	s.task = task
+	s.worker = nil
+	s.filterIndex = max(slices.Index(s.filterOptions, status), 0)
+}
+
+// TaskFilter returns the task the list is limited to, or "".
+func (s *Sidebar) TaskFilter() string {
+	return s.task
+}
+
+// SetShowQueue toggles the queue column, used when listing all queues.
+func (s *Sidebar) SetShowQueue(show bool) {
+	s.list.SetDelegate(jobItemDelegate{showQueue: show})
+}
+
+// SetWorkerFilter limits the list to one worker's doing jobs, or clears
+// the worker filter when id is nil. The status filter is set to doing.
+func (s *Sidebar) SetWorkerFilter(id *int64) {
+	s.worker = id
+	if id != nil {
+		s.task = ""
+		s.filterIndex = max(slices.Index(s.filterOptions, string(db.StatusDoing)), 0)
+	}
+}
+
+// WorkerFilter returns the worker the list is limited to, or nil.
+func (s *Sidebar) WorkerFilter() *int64 {
+	return s.worker
+}
+
+// SetQuery limits the list to jobs matching a filter expression, on top of
+// the other filters; nil clears it.
+func (s *Sidebar) SetQuery(q *db.Query) {
+	s.query = q
+}
+
+// Query returns the filter expression the list is limited to, or nil.
+func (s *Sidebar) Query() *db.Query {
+	return s.query
 }
 
 // SetSize updates the sidebar dimensions.
@@ -130,11 +344,6 @@ func (s *Sidebar) SetFocused(f bool) {
 	s.focused = f
 }
 
-// IsFiltering returns true when the user is actively typing in the filter input.
-func (s *Sidebar) IsFiltering() bool {
-	return s.list.SettingFilter()
-}
-
 // Update handles messages for the sidebar.
 func (s Sidebar) Update(msg tea.Msg) (Sidebar, tea.Cmd) {
 	// Block key input when unfocused, but always allow non-key messages
@@ -157,12 +366,18 @@ func (s Sidebar) View() string {
 	}
 
 	title := TitleStyle.Render(" Jobs ")
-	count := lipgloss.NewStyle().Foreground(ColorMuted).Render(
-		fmt.Sprintf("(%d)", len(s.jobs)),
-	)
-	filterLabel := lipgloss.NewStyle().Foreground(ColorWarning).Bold(true).Render(
-		filterLabels[s.filterIndex],
-	)
+	count := lipgloss.NewStyle().Foreground(ColorMuted).Render(s.countLabel())
+	label := filterLabel(s.CurrentFilter())
+	switch {
+	case s.worker != nil:
+		label = fmt.Sprintf("Worker #%d", *s.worker)
+	case s.task != "":
+		label = s.task + " · " + label
+	}
+	if s.query != nil {
+		label = truncate("/"+s.query.String()+" · "+label, max(s.width-len(s.countLabel())-12, 8))
+	}
+	filterLabel := lipgloss.NewStyle().Foreground(ColorWarning).Bold(true).Render(label)
 	header := title + count + " " + filterLabel
 
 	content := s.list.View()
@@ -180,6 +395,27 @@ func (s Sidebar) View() string {
 	)
 }
 
+// countLabel renders "(n)" when everything is loaded, otherwise
+// "(n of ~total)" using the planner estimate.
+func (s Sidebar) countLabel() string {
+	n := int64(len(s.jobs))
+	if s.exhausted || s.total <= n {
+		if s.loadingMore {
+			return fmt.Sprintf("(%d…)", n)
+		}
+		return fmt.Sprintf("(%d)", n)
+	}
+	return fmt.Sprintf("(%d of ~%d)", n, s.total)
+}
+
+// filterLabel returns the display label for a status filter value.
+func filterLabel(status string) string {
+	if status == "" {
+		return "All"
+	}
+	return strings.ToUpper(status[:1]) + status[1:]
+}
+
 // padOrTruncate ensures content fits within the given dimensions.
 func padOrTruncate(content string, width, height int) string {
 	lines := strings.Split(content, "\n")
@@ -1,7 +1,10 @@
 package tui
 
 import (
+	"cmp"
 	"fmt"
+	"math"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
@@ -10,18 +13,177 @@ import (
 	"github.com/matthewmyrick/procrastinate-cli/db"
 )
 
-// StatusView displays job counts grouped by status.
+// matrixLabelWidth and matrixColWidth size the task/queue × status matrix.
+const (
+	matrixLabelWidth = 20
+	matrixColWidth   = 9
+)
+
+// heatColors shade heatmap cells from cold to hot.
+var heatColors = []lipgloss.Color{"#1E3A5F", "#2E6B8A", "#C9A227", "#D9731A", "#C0392B"}
+
+// StatusView displays job counts grouped by status, and below them a
+// task × status matrix with a cell cursor. In all-queues mode the matrix
+// rows are queues and the cells are shaded as a heatmap.
 type StatusView struct {
+	statuses []db.JobStatus // rows to show, from the detected schema
 	counts   []db.StatusCount
 	total    int64
+	trend    *db.BacklogTrend
 	viewport viewport.Model
 	width    int
 	height   int
+
+	// Matrix rows are tasks, or queues when byQueue is set. Column
+	// len(statuses) is the per-row total.
+	byQueue   bool
+	rows      []string
+	matrix    map[string]map[db.JobStatus]int64
+	row, col  int  // cell cursor
+	sortCol   int  // column the rows are sorted by
+	sortAsc   bool // ascending instead of the default descending
+	matrixTop int  // content line of the first matrix row, for scrolling
 }
 
 // NewStatusView creates a new status breakdown view.
 func NewStatusView() StatusView {
-	return StatusView{}
+	statuses := db.AllStatuses()
+	return StatusView{statuses: statuses, sortCol: len(statuses)}
+}
+
+// SetStatuses sets which statuses get a row, in display order.
+func (s *StatusView) SetStatuses(statuses []db.JobStatus) {
+	s.statuses = statuses
+	s.sortCol = len(statuses)
+	s.col = min(s.col, len(statuses))
+	s.refresh()
+}
+
+// SetTaskCounts fills the matrix with one row per task, keeping the cursor
+// on the same task when it still exists.
+func (s *StatusView) SetTaskCounts(counts []db.TaskStatusCount) {
+	current, _ := s.SelectedCell()
+	if s.byQueue {
+		current = ""
+	}
+	s.byQueue = false
+	s.resetMatrix()
+	for _, c := range counts {
+		s.addCell(c.TaskName, c.Status, c.Count)
+	}
+	s.finishMatrix(current)
+}
+
+// SetQueueCounts fills the matrix with one row per queue, shown as a
+// heatmap, keeping the cursor on the same queue when it still exists.
+func (s *StatusView) SetQueueCounts(counts []db.QueueStatusCount) {
+	current, _ := s.SelectedCell()
+	if !s.byQueue {
+		current = ""
+	}
+	s.byQueue = true
+	s.resetMatrix()
+	for _, c := range counts {
+		s.addCell(c.QueueName, c.Status, c.Count)
+	}
+	s.finishMatrix(current)
+}
+
+// ByQueue reports whether the matrix rows are queues rather than tasks.
+func (s *StatusView) ByQueue() bool {
+	return s.byQueue
+}
+
+func (s *StatusView) resetMatrix() {
+	s.matrix = make(map[string]map[db.JobStatus]int64)
+	s.rows = nil
+}
+
+func (s *StatusView) addCell(row string, status db.JobStatus, count int64) {
+	if s.matrix[row] == nil {
+		s.matrix[row] = make(map[db.JobStatus]int64)
+		s.rows = append(s.rows, row)
+	}
+	s.matrix[row][status] = count
+}
+
+func (s *StatusView) finishMatrix(current string) {
+	s.sortRows()
+	s.row = max(slices.Index(s.rows, current), 0)
+	s.refresh()
+}
+
+// MoveCursor moves the matrix cell cursor by the given rows and columns.
+func (s *StatusView) MoveCursor(rows, cols int) {
+	s.row = min(max(s.row+rows, 0), max(len(s.rows)-1, 0))
+	s.col = min(max(s.col+cols, 0), len(s.statuses))
+	s.refresh()
+}
+
+// SortByCursor sorts rows by the highlighted column, descending first;
+// sorting by the same column again reverses the order.
+func (s *StatusView) SortByCursor() {
+	current, _ := s.SelectedCell()
+	if s.sortCol == s.col {
+		s.sortAsc = !s.sortAsc
+	} else {
+		s.sortCol = s.col
+		s.sortAsc = false
+	}
+	s.sortRows()
+	s.row = max(slices.Index(s.rows, current), 0)
+	s.refresh()
+}
+
+// SelectedCell returns the highlighted row (a task, or a queue in
+// all-queues mode) and status. The status is empty for the total column;
+// the row is empty when the matrix is empty.
+func (s *StatusView) SelectedCell() (row, status string) {
+	if s.row >= len(s.rows) {
+		return "", ""
+	}
+	if s.col < len(s.statuses) {
+		status = string(s.statuses[s.col])
+	}
+	return s.rows[s.row], status
+}
+
+// cell returns the count for a row in a column.
+func (s *StatusView) cell(row string, col int) int64 {
+	if col < len(s.statuses) {
+		return s.matrix[row][s.statuses[col]]
+	}
+	var total int64
+	for _, n := range s.matrix[row] {
+		total += n
+	}
+	return total
+}
+
+func (s *StatusView) sortRows() {
+	slices.SortStableFunc(s.rows, func(a, b string) int {
+		if c := cmp.Compare(s.cell(a, s.sortCol), s.cell(b, s.sortCol)); c != 0 {
+			if s.sortAsc {
+				return c
+			}
+			return -c
+		}
+		return strings.Compare(a, b)
+	})
+}
+
+// refresh re-renders the content and scrolls to keep the cursor row visible.
+func (s *StatusView) refresh() {
+	s.viewport.SetContent(s.renderContent())
+	if len(s.rows) == 0 {
+		return
+	}
+	line := s.matrixTop + s.row
+	if line < s.viewport.YOffset {
+		s.viewport.SetYOffset(line)
+	} else if line >= s.viewport.YOffset+s.viewport.Height {
+		s.viewport.SetYOffset(line - s.viewport.Height + 1)
+	}
 }
 
 // SetCounts updates the status counts and re-renders content.
@@ -31,7 +193,13 @@ func (s *StatusView) SetCounts(counts []db.StatusCount) {
 	for _, c := range counts {
 		s.total += c.Count
 	}
-	s.viewport.SetContent(s.renderContent())
+	s.refresh()
+}
+
+// SetTrend updates the backlog trend shown under the totals; nil hides it.
+func (s *StatusView) SetTrend(trend *db.BacklogTrend) {
+	s.trend = trend
+	s.refresh()
 }
 
 // SetSize updates the viewport dimensions.
@@ -40,7 +208,7 @@ func (s *StatusView) SetSize(width, height int) {
 	s.height = height
 	s.viewport.Width = width
 	s.viewport.Height = height
-	s.viewport.SetContent(s.renderContent())
+	s.refresh()
 }
 
 // Update handles messages for the status view.
@@ -84,7 +252,7 @@ func (s *StatusView) renderContent() string {
 		maxBarWidth = 10
 	}
 
-	for _, status := range db.AllStatuses() {
+	for _, status := range s.statuses {
 		count := countMap[status]
 		style := StatusStyle(string(status))
 
@@ -113,5 +281,156 @@ func (s *StatusView) renderContent() string {
 	b.WriteString(totalStyle.Render(fmt.Sprintf("  %-14s %8d", "Total", s.total)))
 	b.WriteString("\n")
 
+	s.renderTrend(&b)
+	s.renderMatrix(&b)
+
 	return b.String()
 }
+
+// renderTrend writes a sparkline of the todo backlog with the enqueue and
+// dequeue rates and an estimate of when the backlog drains.
+func (s *StatusView) renderTrend(b *strings.Builder) {
+	if s.trend == nil {
+		return
+	}
+	t := s.trend
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorWhite)
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+
+	b.WriteString("\n")
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  Backlog (%s)", formatWindow(t.Window()))))
+	b.WriteString("  ")
+	b.WriteString(StatusStyle(string(db.StatusTodo)).Render(sparkline(t.Series)))
+	b.WriteString(lipgloss.NewStyle().Foreground(ColorWhite).Render(fmt.Sprintf("  %d todo", t.Todo)))
+	b.WriteString("\n")
+
+	var outlook string
+	drain, ok := t.DrainTime()
+	switch {
+	case t.Todo == 0:
+		outlook = lipgloss.NewStyle().Foreground(ColorSecondary).Render("empty")
+	case ok:
+		outlook = lipgloss.NewStyle().Foreground(ColorSecondary).Render("drains in ~" + formatDuration(drain))
+	case t.Dequeued == 0:
+		outlook = lipgloss.NewStyle().Foreground(ColorError).Bold(true).Render("stalled: nothing started")
+	case t.Enqueued == t.Dequeued:
+		outlook = lipgloss.NewStyle().Foreground(ColorWarning).Render("steady")
+	default:
+		outlook = lipgloss.NewStyle().Foreground(ColorError).Bold(true).Render("growing")
+	}
+	b.WriteString(muted.Render(fmt.Sprintf("  in %.1f/min · out %.1f/min · ", t.EnqueueRate(), t.DequeueRate())))
+	b.WriteString(outlook)
+	b.WriteString("\n")
+}
+
+// sparkBlocks are the sparkline glyphs, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders values as block glyphs scaled between their minimum
+// and maximum.
+func sparkline(values []int64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := slices.Min(values), slices.Max(values)
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if hi > lo {
+			level = int(float64(v-lo) / float64(hi-lo) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// renderMatrix writes the task or queue × status matrix. Columns that do
+// not fit are scrolled so the cursor column stays visible.
+func (s *StatusView) renderMatrix(b *strings.Builder) {
+	if len(s.rows) == 0 {
+		return
+	}
+
+	cols := len(s.statuses) + 1
+	visible := max((s.width-4-matrixLabelWidth)/matrixColWidth, 1)
+	first := 0
+	if s.col >= visible {
+		first = s.col - visible + 1
+	}
+	last := min(first+visible, cols)
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorWhite)
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+
+	b.WriteString("\n")
+	title, rowLabel, hint := "  By task", "Task", "filter sidebar"
+	if s.byQueue {
+		title, rowLabel, hint = "  Queue heatmap", "Queue", "open queue"
+	}
+	b.WriteString(headerStyle.Render(title))
+	b.WriteString(muted.Render("  · arrows: move · s: sort · enter: " + hint))
+	b.WriteString("\n")
+
+	header := fmt.Sprintf("  %-*s", matrixLabelWidth, rowLabel)
+	for c := first; c < last; c++ {
+		label := "total"
+		if c < len(s.statuses) {
+			label = string(s.statuses[c])
+		}
+		if c == s.sortCol {
+			if s.sortAsc {
+				label += "↑"
+			} else {
+				label += "↓"
+			}
+		}
+		header += fmt.Sprintf("%*s", matrixColWidth, truncate(label, matrixColWidth-1))
+	}
+	b.WriteString(headerStyle.Render(header))
+	b.WriteString("\n")
+	b.WriteString(muted.Render("  " + strings.Repeat("─", s.width-4)))
+	b.WriteString("\n")
+
+	s.matrixTop = strings.Count(b.String(), "\n")
+
+	// Heatmap shading is relative to each column's largest cell
+	colMax := make([]int64, cols)
+	for _, row := range s.rows {
+		for c := range cols {
+			colMax[c] = max(colMax[c], s.cell(row, c))
+		}
+	}
+
+	cursorStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorWhite).Background(ColorDim)
+	for r, row := range s.rows {
+		line := fmt.Sprintf("  %-*s", matrixLabelWidth, truncate(row, matrixLabelWidth-1))
+		for c := first; c < last; c++ {
+			n := s.cell(row, c)
+			text := fmt.Sprintf("%*d", matrixColWidth, n)
+			style := muted
+			switch {
+			case r == s.row && c == s.col:
+				style = cursorStyle
+			case n > 0 && s.byQueue:
+				style = lipgloss.NewStyle().Foreground(ColorWhite).Background(heatColor(n, colMax[c]))
+			case n > 0 && c < len(s.statuses):
+				style = StatusStyle(string(s.statuses[c]))
+			case n > 0:
+				style = lipgloss.NewStyle().Foreground(ColorWhite)
+			}
+			line += style.Render(text)
+		}
+		b.WriteString(line + "\n")
+	}
+}
+
+// heatColor picks a shade for n on a log scale up to peak, so small
+// counts stay distinguishable next to large ones.
+func heatColor(n, peak int64) lipgloss.Color {
+	level := 0
+	if peak > 0 {
+		ratio := math.Log1p(float64(n)) / math.Log1p(float64(peak))
+		level = min(int(ratio*float64(len(heatColors))), len(heatColors)-1)
+	}
+	return heatColors[level]
+}
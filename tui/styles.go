@@ -12,6 +12,9 @@ var (
 	ColorWhite     = lipgloss.Color("#FFFFFF")
 	ColorDim       = lipgloss.Color("#4A4A4A")
 
+	// Top bar background for production connections
+	ColorProductionBar = lipgloss.Color("#5A1010")
+
 	// Status-specific colors
 	ColorTodo      = lipgloss.Color("#04B575")
 	ColorDoing     = lipgloss.Color("#FFAA00")
@@ -27,6 +30,11 @@ var (
 			Bold(true).
 			Padding(0, 1)
 
+	TopBarProductionStyle = lipgloss.NewStyle().
+				Bold(true).
+				Padding(0, 1).
+				Background(ColorProductionBar)
+
 	TopBarQueueStyle = lipgloss.NewStyle().
 				Bold(true).
 				Foreground(ColorSecondary)
@@ -105,12 +113,47 @@ var (
 			Padding(0, 2).
 			Bold(true)
 
+	ToastSuccessStyle = lipgloss.NewStyle().
+				Foreground(ColorWhite).
+				Background(lipgloss.Color("#037A50")).
+				Padding(0, 2).
+				Bold(true)
+
+	HighlightStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#1A1A1A")).
+			Background(ColorWarning).
+			Bold(true)
+
 	OverlayStyle = lipgloss.NewStyle().
 			BorderStyle(lipgloss.DoubleBorder()).
 			BorderForeground(ColorPrimary).
 			Padding(1, 2)
 )
 
+// Badge styles for the top bar
+var (
+	ReadOnlyBadgeStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ColorWhite).
+		Background(ColorDim).
+		Padding(0, 1)
+)
+
+// EnvironmentBadgeStyle returns the badge style for a connection environment.
+func EnvironmentBadgeStyle(env string) lipgloss.Style {
+	base := lipgloss.NewStyle().Bold(true).Foreground(ColorWhite).Padding(0, 1)
+	switch env {
+	case "production":
+		return base.Background(ColorError)
+	case "staging":
+		return base.Background(lipgloss.Color("#B37400"))
+	case "development":
+		return base.Background(lipgloss.Color("#037A50"))
+	default:
+		return base.Background(ColorPrimary)
+	}
+}
+
 // StatusStyle returns the appropriate style for a job status.
 func StatusStyle(status string) lipgloss.Style {
 	base := lipgloss.NewStyle().Bold(true)
@@ -4,13 +4,19 @@ import "github.com/charmbracelet/lipgloss"
 
 // Tab indices
 const (
-	TabStatus   = 0
-	TabLive     = 1
-	TabOrphaned = 2
+	TabStatus    = 0
+	TabLive      = 1
+	TabOrphaned  = 2
+	TabWorkers   = 3
+	TabPeriodic  = 4
+	TabLocks     = 5
+	TabScheduled = 6
+	TabMetrics   = 7
+	TabFailures  = 8
 )
 
 // TabNames are the display names for each tab.
-var TabNames = []string{"Status", "Live", "Orphaned"}
+var TabNames = []string{"Status", "Live", "Orphaned", "Workers", "Periodic", "Locks", "Scheduled", "Metrics", "Failures"}
 
 // TabBar manages the tab strip in the detail pane.
 type TabBar struct {
@@ -54,18 +60,63 @@ func (t *TabBar) SetWidth(w int) {
 	t.width = w
 }
 
-// View renders the tab bar.
+// View renders the tab bar. When the tabs do not fit they are drawn with
+// less padding, and if that is still too wide only a window of tabs around
+// the active one is shown.
 func (t TabBar) View() string {
+	row := t.renderTabs(0, len(t.tabs), 2)
+	if t.width > 0 && lipgloss.Width(row) > t.width {
+		row = t.renderTabs(0, len(t.tabs), 1)
+	}
+	if t.width > 0 && lipgloss.Width(row) > t.width {
+		start, end := t.activeTab, t.activeTab+1
+		for {
+			grown := false
+			if end < len(t.tabs) && t.fits(start, end+1) {
+				end++
+				grown = true
+			}
+			if start > 0 && t.fits(start-1, end) {
+				start--
+				grown = true
+			}
+			if !grown {
+				break
+			}
+		}
+		row = t.renderWindow(start, end)
+	}
+
+	return TabBarStyle.Width(t.width).Render(row)
+}
+
+// fits reports whether tabs[start:end] fit with compact padding and the
+// scroll markers.
+func (t TabBar) fits(start, end int) bool {
+	return lipgloss.Width(t.renderWindow(start, end)) <= t.width
+}
+
+// renderWindow renders tabs[start:end] with markers for hidden tabs.
+func (t TabBar) renderWindow(start, end int) string {
+	marker := InactiveTabStyle.Padding(0)
+	left, right := " ", " "
+	if start > 0 {
+		left = "‹"
+	}
+	if end < len(t.tabs) {
+		right = "›"
+	}
+	return marker.Render(left) + t.renderTabs(start, end, 1) + marker.Render(right)
+}
+
+func (t TabBar) renderTabs(start, end, padding int) string {
 	var rendered []string
-	for i, name := range t.tabs {
+	for i := start; i < end; i++ {
 		if i == t.activeTab {
-			rendered = append(rendered, ActiveTabStyle.Render(name))
+			rendered = append(rendered, ActiveTabStyle.Padding(0, padding).Render(t.tabs[i]))
 		} else {
-			rendered = append(rendered, InactiveTabStyle.Render(name))
+			rendered = append(rendered, InactiveTabStyle.Padding(0, padding).Render(t.tabs[i]))
 		}
 	}
-
-	row := lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
-
-	return TabBarStyle.Width(t.width).Render(row)
+	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
 }
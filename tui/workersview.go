@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/matthewmyrick/procrastinate-cli/db"
+)
+
+// workersHeaderLines is the number of lines rendered above the first worker row.
+const workersHeaderLines = 4
+
+// WorkersView lists Procrastinate workers, their heartbeat and held jobs.
+type WorkersView struct {
+	workers   []db.Worker
+	threshold time.Duration // heartbeat age after which a worker counts as dead
+	err       error
+	cursor    int
+	viewport  viewport.Model
+	width     int
+	height    int
+}
+
+// NewWorkersView creates a new workers view.
+func NewWorkersView(threshold time.Duration) WorkersView {
+	return WorkersView{threshold: threshold}
+}
+
+// SetWorkers updates the worker data.
+func (w *WorkersView) SetWorkers(workers []db.Worker, err error) {
+	w.workers = workers
+	w.err = err
+	if w.cursor >= len(workers) {
+		w.cursor = max(len(workers)-1, 0)
+	}
+	w.refresh()
+}
+
+// MoveCursor moves the highlighted row by delta, clamped to the list.
+func (w *WorkersView) MoveCursor(delta int) {
+	w.cursor = min(max(w.cursor+delta, 0), max(len(w.workers)-1, 0))
+	w.refresh()
+}
+
+// Selected returns the highlighted worker, or nil if there are none.
+func (w *WorkersView) Selected() *db.Worker {
+	if w.cursor >= len(w.workers) {
+		return nil
+	}
+	return &w.workers[w.cursor]
+}
+
+// SetSize updates the viewport dimensions.
+func (w *WorkersView) SetSize(width, height int) {
+	w.width = width
+	w.height = height
+	w.viewport.Width = width
+	w.viewport.Height = height
+	w.refresh()
+}
+
+// Update handles messages for the workers view.
+func (w WorkersView) Update(msg tea.Msg) (WorkersView, tea.Cmd) {
+	var cmd tea.Cmd
+	w.viewport, cmd = w.viewport.Update(msg)
+	return w, cmd
+}
+
+// View renders the workers view.
+func (w WorkersView) View() string {
+	return w.viewport.View()
+}
+
+// refresh re-renders the content and scrolls to keep the cursor visible.
+func (w *WorkersView) refresh() {
+	w.viewport.SetContent(w.renderContent())
+	if len(w.workers) == 0 {
+		return
+	}
+	line := workersHeaderLines + w.cursor
+	if line < w.viewport.YOffset {
+		w.viewport.SetYOffset(line)
+	} else if line >= w.viewport.YOffset+w.viewport.Height {
+		w.viewport.SetYOffset(line - w.viewport.Height + 1)
+	}
+}
+
+func (w *WorkersView) alive(worker db.Worker, now time.Time) bool {
+	return worker.LastHeartbeat != nil && now.Sub(*worker.LastHeartbeat) < w.threshold
+}
+
+func (w *WorkersView) renderContent() string {
+	if w.err != nil {
+		return lipgloss.NewStyle().
+			Foreground(ColorMuted).
+			Padding(1, 1).
+			Render(w.err.Error())
+	}
+	if len(w.workers) == 0 {
+		return lipgloss.NewStyle().
+			Foreground(ColorMuted).
+			Padding(1, 1).
+			Render("No workers registered")
+	}
+
+	now := time.Now()
+	alive := 0
+	for _, worker := range w.workers {
+		if w.alive(worker, now) {
+			alive++
+		}
+	}
+
+	var b strings.Builder
+
+	// Summary
+	summaryStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorWhite)
+	b.WriteString(summaryStyle.Render(
+		fmt.Sprintf("  %d worker(s): %d alive, %d dead (threshold %s)",
+			len(w.workers), alive, len(w.workers)-alive, w.threshold)))
+	b.WriteString("\n\n")
+
+	// Column headers
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorWhite)
+	b.WriteString(headerStyle.Render(
+		fmt.Sprintf("  %-8s %-8s %-12s %s", "Worker", "State", "Heartbeat", "Doing Jobs")))
+	b.WriteString("\n")
+	b.WriteString(lipgloss.NewStyle().Foreground(ColorMuted).Render(
+		"  " + strings.Repeat("─", w.width-4)))
+	b.WriteString("\n")
+
+	for i, worker := range w.workers {
+		cursor := "  "
+		if i == w.cursor {
+			cursor = lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render("► ")
+		}
+
+		id := fmt.Sprintf("#%d", worker.ID)
+
+		state := lipgloss.NewStyle().Foreground(ColorSecondary).Bold(true).Render(fmt.Sprintf("%-8s", "alive"))
+		if !w.alive(worker, now) {
+			state = lipgloss.NewStyle().Foreground(ColorError).Bold(true).Render(fmt.Sprintf("%-8s", "dead"))
+		}
+
+		heartbeat := "never"
+		if worker.LastHeartbeat != nil {
+			heartbeat = formatDuration(now.Sub(*worker.LastHeartbeat)) + " ago"
+		}
+		heartbeat = lipgloss.NewStyle().Foreground(ColorMuted).Render(fmt.Sprintf("%-12s", heartbeat))
+
+		jobs := lipgloss.NewStyle().Foreground(ColorMuted).Render("-")
+		if len(worker.DoingJobIDs) > 0 {
+			jobs = StatusStyle("doing").Render(summarizeJobIDs(worker.DoingJobIDs, 5))
+		}
+
+		b.WriteString(fmt.Sprintf("%s%-8s %s %s %s\n", cursor, id, state, heartbeat, jobs))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(lipgloss.NewStyle().Foreground(ColorMuted).Render(
+		"  enter: show the selected worker's doing jobs in the sidebar"))
+
+	return b.String()
+}
+
+// summarizeJobIDs lists up to limit IDs followed by a count of the rest.
+func summarizeJobIDs(ids []int64, limit int) string {
+	parts := make([]string, 0, min(len(ids), limit))
+	for _, id := range ids[:min(len(ids), limit)] {
+		parts = append(parts, fmt.Sprintf("#%d", id))
+	}
+	s := fmt.Sprintf("%d: %s", len(ids), strings.Join(parts, " "))
+	if len(ids) > limit {
+		s += fmt.Sprintf(" +%d more", len(ids)-limit)
+	}
+	return s
+}